@@ -0,0 +1,117 @@
+package excelio
+
+import "fmt"
+
+/* =========================================================
+ *  Automatic header row detection
+ * ========================================================= */
+
+// AutoHeader finds the header row instead of taking it from Header(n): the
+// first maxScanRows rows are scored by how many header-mapped fields of the
+// struct (`excel` names, `excelre` patterns, MatchHeader predicates) they
+// match, and the best row wins (the top-most on a tie). Data starts on the
+// next row. Title blocks, logos and blank rows above the header are skipped.
+//
+// The detected row is reported through OnHeader, InspectHeaders and
+// SheetResult.HeaderRow. Reading fails if no row in range matches any field.
+func AutoHeader(maxScanRows int) Option {
+	return func(o *Options) { o.AutoHeaderRows = maxScanRows }
+}
+
+// OnHeader registers a callback that receives the HeaderReport of the sheet
+// once its header row has been bound, before the first data row is delivered.
+func OnHeader(fn func(HeaderReport)) Option {
+	return func(o *Options) { o.onHeader = fn }
+}
+
+// bufferedRow is a row read ahead during header detection.
+type bufferedRow struct {
+	cols  []string
+	kinds []cellKind
+	err   error
+}
+
+// replayRows returns buffered rows first, then continues with the source.
+type replayRows struct {
+	rowIterator
+	buf []bufferedRow
+	cur int // 1-based position in buf; > len(buf) once buf is exhausted
+}
+
+func (r *replayRows) Next() bool {
+	if r.cur < len(r.buf) {
+		r.cur++
+		return true
+	}
+	r.cur = len(r.buf) + 1
+	return r.rowIterator.Next()
+}
+
+func (r *replayRows) Columns() ([]string, error) {
+	if r.cur <= len(r.buf) {
+		b := r.buf[r.cur-1]
+		return b.cols, b.err
+	}
+	return r.rowIterator.Columns()
+}
+
+func (r *replayRows) cellKinds() []cellKind {
+	if r.cur <= len(r.buf) {
+		return r.buf[r.cur-1].kinds
+	}
+	return rowKinds(r.rowIterator)
+}
+
+// detectHeader reads up to o.AutoHeaderRows rows, sets o.HeaderRow and
+// o.FirstDataRow to the best matching row, and returns an iterator that
+// replays the rows read ahead.
+func (sc *sheetScanner[T]) detectHeader(rows rowIterator) (rowIterator, error) {
+	o := sc.o
+	buf := make([]bufferedRow, 0, o.AutoHeaderRows)
+	for len(buf) < o.AutoHeaderRows && rows.Next() {
+		if err := ctxErr(o.ctx); err != nil {
+			return nil, err
+		}
+		cols, err := rows.Columns()
+		buf = append(buf, bufferedRow{cols: cols, kinds: rowKinds(rows), err: err})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("excelio: read row %d: %w", len(buf)+1, err)
+	}
+
+	best, bestScore := 0, 0
+	for i, br := range buf {
+		if br.err != nil || isRowEmpty(br.cols) {
+			continue
+		}
+		if score := sc.headerScore(br.cols); score > bestScore {
+			best, bestScore = i+1, score
+		}
+	}
+	if bestScore == 0 {
+		return nil, fmt.Errorf("excelio: no header row found in the first %d rows", o.AutoHeaderRows)
+	}
+
+	o.HeaderRow = best
+	o.HeaderRowEnd = 0
+	o.FirstDataRow = best + 1
+	return &replayRows{rowIterator: rows, buf: buf}, nil
+}
+
+// headerScore counts the fields bound by header text or pattern if cols were
+// the header row. Rows with ambiguous patterns score 0.
+func (sc *sheetScanner[T]) headerScore(cols []string) int {
+	headerMap, headerIndex := parseHeader(cols, sc.o)
+	fieldColIndex, matches, err := buildFieldColIndex(sc.meta, headerMap, headerIndex, sc.o)
+	if err != nil {
+		return 0
+	}
+	_, multiMatches := bindMultiColumns(sc.meta, headerMap, headerIndex, fieldColIndex, sc.o)
+	score := 0
+	for _, m := range append(matches, multiMatches...) {
+		if m.By != MatchByCol && m.By != MatchByLetter && m.By != MatchByRest {
+			score++
+		}
+	}
+	return score
+}
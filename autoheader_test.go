@@ -0,0 +1,97 @@
+package excelio
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type autoRow struct {
+	Code  string  `excel:"Code"`
+	Name  string  `excel:"Name"`
+	Price float64 `excel:"Price"`
+}
+
+// autoFixture has a title block, a blank row and a partial "header" above the
+// real header in row 5.
+func autoFixture(t *testing.T) []byte {
+	t.Helper()
+	f := excelize.NewFile()
+	defer f.Close()
+	sheet := f.GetSheetName(0)
+	for cell, row := range map[string][]any{
+		"A1": {"ACME Price List"},
+		"A2": {"Generated", "2024-03-05"},
+		"A4": {"Code", "see notes"},
+		"A5": {"Code", "Name", "Price"},
+		"A6": {"P1", "Pen", 1.5},
+		"A7": {"P2", "Ink", 3},
+		"A9": {"P3", "Pad", 2.25},
+	} {
+		if err := f.SetSheetRow(sheet, cell, &row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestAutoHeader(t *testing.T) {
+	data := autoFixture(t)
+	want := []autoRow{{"P1", "Pen", 1.5}, {"P2", "Ink", 3}, {"P3", "Pad", 2.25}}
+	wantRows := []int{6, 7, 9}
+
+	// 7 rows: the data rows 6 and 7 are read ahead and replayed.
+	for _, scan := range []int{5, 7, 20} {
+		var header HeaderReport
+		var rows []int
+		var got []autoRow
+		_, err := Stream[autoRow](bytes.NewReader(data), AutoHeader(scan),
+			OnHeader(func(r HeaderReport) { header = r }),
+			OnStreamRow(func(rowIdx, logicalIdx int, obj *autoRow, rowErrs []RowError) error {
+				if obj == nil {
+					t.Errorf("scan %d: row %d: %v", scan, rowIdx, rowErrs)
+					return nil
+				}
+				rows = append(rows, rowIdx)
+				got = append(got, *obj)
+				return nil
+			}))
+		if err != nil {
+			t.Fatalf("scan %d: %v", scan, err)
+		}
+		if header.HeaderRow != 5 || len(header.Matched) != 3 {
+			t.Errorf("scan %d: header row %d, matched %v", scan, header.HeaderRow, header.Matched)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("scan %d: got %+v, want %+v", scan, got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] || rows[i] != wantRows[i] {
+				t.Errorf("scan %d: row %d = %+v, want row %d %+v", scan, rows[i], got[i], wantRows[i], want[i])
+			}
+		}
+	}
+
+	// The header is beyond the scanned rows.
+	_, _, err := Read[autoRow](bytes.NewReader(data), AutoHeader(3))
+	if err == nil || !strings.Contains(err.Error(), "no header row found in the first 3 rows") {
+		t.Errorf("err = %v, want no header row found", err)
+	}
+}
+
+func TestAutoHeaderCSV(t *testing.T) {
+	src := "Export\n\nname,code,price\nPen,P1,1.5\n"
+	res, err := ReadSheets(strings.NewReader(src), SheetInto(new([]autoRow), Format(FormatCSV), AutoHeader(10)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res[0].HeaderRow != 3 || res[0].Rows != 1 || len(res[0].Errors) > 0 {
+		t.Errorf("result = %+v, want header row 3 and 1 row", res[0])
+	}
+}
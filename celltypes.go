@@ -0,0 +1,364 @@
+package excelio
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+/* =========================================================
+ *  XLSX cell types (RawValues)
+ * ========================================================= */
+
+// cellKind is the stored type of a cell. excelize.Rows only returns values,
+// so with RawValues the types are streamed from the worksheet XML alongside.
+type cellKind uint8
+
+const (
+	cellUnknown cellKind = iota // Not known: formatted text, CSV/TSV
+	cellText                    // Shared, inline or formula string; also tag defaults
+	cellNumber                  // Number or numeric formula result without a date format
+	cellDate                    // Number with a date/time format, or an ISO 8601 date cell
+	cellBool
+	cellError
+)
+
+// numeric reports whether the stored value is a number (dates are serials).
+func (k cellKind) numeric() bool { return k == cellNumber || k == cellDate }
+
+// kindedRows is implemented by row iterators that know the cell types of the
+// current row.
+type kindedRows interface {
+	// cellKinds returns the kind of each cell of the current row by 0-based
+	// column; columns past the end are cellUnknown.
+	cellKinds() []cellKind
+}
+
+// rowKinds returns the cell kinds of the current row of rows, or nil.
+func rowKinds(rows rowIterator) []cellKind {
+	if kr, ok := rows.(kindedRows); ok {
+		return kr.cellKinds()
+	}
+	return nil
+}
+
+// kindAt returns the kind of column col, or cellUnknown.
+func kindAt(kinds []cellKind, col int) cellKind {
+	if col < 0 || col >= len(kinds) {
+		return cellUnknown
+	}
+	return kinds[col]
+}
+
+// builtinDateFormats are the built-in number format IDs that display dates or
+// times, including the East Asian ones.
+var builtinDateFormats = map[int]bool{
+	14: true, 15: true, 16: true, 17: true, 18: true, 19: true, 20: true, 21: true, 22: true,
+	27: true, 28: true, 29: true, 30: true, 31: true, 32: true, 33: true, 34: true, 35: true, 36: true,
+	45: true, 46: true, 47: true,
+	50: true, 51: true, 52: true, 53: true, 54: true, 55: true, 56: true, 57: true, 58: true,
+	71: true, 72: true, 73: true, 74: true, 75: true, 76: true, 77: true, 78: true, 79: true,
+	80: true, 81: true,
+}
+
+// isDateFormatCode reports whether a custom number format code displays a
+// date or time: it has a y, d, h or s token outside quoted text, escapes and
+// [color] / [$-locale] sections ("[h]:mm" elapsed time counts too). Only the
+// first (positive) section is examined.
+func isDateFormatCode(code string) bool {
+	if section, _, ok := strings.Cut(code, ";"); ok {
+		code = section
+	}
+	for i := 0; i < len(code); i++ {
+		switch c := code[i]; c {
+		case '"':
+			if j := strings.IndexByte(code[i+1:], '"'); j >= 0 {
+				i += j + 1
+			}
+		case '\\', '_', '*':
+			i++
+		case '[':
+			j := strings.IndexByte(code[i:], ']')
+			if j < 0 {
+				return false
+			}
+			if inner := strings.ToLower(code[i+1 : i+j]); inner == "h" || inner == "hh" ||
+				inner == "m" || inner == "mm" || inner == "s" || inner == "ss" {
+				return true
+			}
+			i += j
+		case 'y', 'Y', 'd', 'D', 'h', 'H', 's', 'S':
+			return true
+		}
+	}
+	return false
+}
+
+// sheetCellKinds streams the cell types of one worksheet in step with
+// excelize.Rows. Only the row being read is kept in memory.
+type sheetCellKinds struct {
+	f      *excelize.File
+	dec    *xml.Decoder
+	closer io.Closer
+
+	row   int        // number of the buffered row; 0 before the first
+	kinds []cellKind // kinds of the buffered row
+	done  bool       // </sheetData> or the end of the part was reached
+
+	dateStyles map[int]bool // style ID -> has a date/time number format
+}
+
+// openCellKinds opens the worksheet part of sheet in the archive of b.
+func openCellKinds(b *xlsxBook, sheet string) (*sheetCellKinds, error) {
+	zr, closer, err := b.archive()
+	if err != nil {
+		return nil, err
+	}
+	fail := func(err error) (*sheetCellKinds, error) {
+		if closer != nil {
+			_ = closer.Close()
+		}
+		return nil, fmt.Errorf("excelio: cell types: %w", err)
+	}
+	part, err := worksheetPart(zr, sheet)
+	if err != nil {
+		return fail(err)
+	}
+	zf, err := zr.Open(part)
+	if err != nil {
+		return fail(err)
+	}
+	return &sheetCellKinds{
+		f:          b.f,
+		dec:        xml.NewDecoder(zf),
+		closer:     multiCloser{zf, closer},
+		dateStyles: make(map[int]bool),
+	}, nil
+}
+
+func (s *sheetCellKinds) Close() error { return s.closer.Close() }
+
+// at returns the cell kinds of row n (1-based). Rows must be requested in
+// increasing order; missing rows have no kinds.
+func (s *sheetCellKinds) at(n int) ([]cellKind, error) {
+	for !s.done && s.row < n {
+		if err := s.readRow(); err != nil {
+			s.done = true
+			return nil, err
+		}
+	}
+	if s.row == n {
+		return s.kinds, nil
+	}
+	return nil, nil
+}
+
+// readRow reads the next <row> element into s.row and s.kinds.
+func (s *sheetCellKinds) readRow() error {
+	for {
+		tok, err := s.dec.Token()
+		if err == io.EOF {
+			s.done = true
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch el := tok.(type) {
+		case xml.StartElement:
+			if el.Name.Local != "row" {
+				continue
+			}
+			s.row++
+			if r := xmlAttr(el, "r"); r != "" {
+				if s.row, err = strconv.Atoi(r); err != nil {
+					return fmt.Errorf("row number %q: %w", r, err)
+				}
+			}
+			return s.readCells()
+		case xml.EndElement:
+			if el.Name.Local == "sheetData" {
+				s.done = true
+				return nil
+			}
+		}
+	}
+}
+
+// readCells reads the <c> elements of the current row up to </row>.
+func (s *sheetCellKinds) readCells() error {
+	s.kinds = nil // rows may be mapped after the next one is read
+	col := 0
+	for {
+		tok, err := s.dec.Token()
+		if err != nil {
+			return err
+		}
+		switch el := tok.(type) {
+		case xml.StartElement:
+			if el.Name.Local != "c" {
+				if err := s.dec.Skip(); err != nil {
+					return err
+				}
+				continue
+			}
+			col++
+			if ref := xmlAttr(el, "r"); ref != "" {
+				if col, _, err = excelize.CellNameToCoordinates(ref); err != nil {
+					return err
+				}
+			}
+			for len(s.kinds) < col {
+				s.kinds = append(s.kinds, cellUnknown)
+			}
+			s.kinds[col-1] = s.kindOf(xmlAttr(el, "t"), xmlAttr(el, "s"))
+			if err := s.dec.Skip(); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			if el.Name.Local == "row" {
+				return nil
+			}
+		}
+	}
+}
+
+// kindOf maps the t (type) and s (style) attributes of a <c> element.
+func (s *sheetCellKinds) kindOf(t, style string) cellKind {
+	switch t {
+	case "s", "inlineStr", "str":
+		return cellText
+	case "b":
+		return cellBool
+	case "e":
+		return cellError
+	case "d":
+		return cellDate
+	}
+	if id, err := strconv.Atoi(style); err == nil && s.isDateStyle(id) {
+		return cellDate
+	}
+	return cellNumber
+}
+
+// isDateStyle reports whether style id has a date/time number format.
+func (s *sheetCellKinds) isDateStyle(id int) bool {
+	if id == 0 {
+		return false
+	}
+	date, ok := s.dateStyles[id]
+	if !ok {
+		if st, err := s.f.GetStyle(id); err == nil && st != nil {
+			date = builtinDateFormats[st.NumFmt] || (st.CustomNumFmt != nil && isDateFormatCode(*st.CustomNumFmt))
+		}
+		s.dateStyles[id] = date
+	}
+	return date
+}
+
+// xmlAttr returns the value of the attribute with the given local name.
+func xmlAttr(el xml.StartElement, name string) string {
+	for _, a := range el.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// archive opens the zip archive the workbook was read from. The closer, if
+// not nil, must be closed after use.
+func (b *xlsxBook) archive() (*zip.Reader, io.Closer, error) {
+	if b.data != nil {
+		zr, err := zip.NewReader(bytes.NewReader(b.data), int64(len(b.data)))
+		return zr, nil, err
+	}
+	file, err := os.Open(b.path)
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, nil, err
+	}
+	zr, err := zip.NewReader(file, info.Size())
+	if err != nil {
+		_ = file.Close()
+		return nil, nil, err
+	}
+	return zr, file, nil
+}
+
+// worksheetPart resolves the archive path of the worksheet named sheet
+// through the workbook and its relationships.
+func worksheetPart(zr *zip.Reader, sheet string) (string, error) {
+	const workbook = "xl/workbook.xml"
+	var wb struct {
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+			ID   string `xml:"id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := decodeZipXML(zr, workbook, &wb); err != nil {
+		return "", err
+	}
+	var rels struct {
+		Rels []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := decodeZipXML(zr, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return "", err
+	}
+	for _, sh := range wb.Sheets {
+		if !strings.EqualFold(sh.Name, sheet) {
+			continue
+		}
+		for _, rel := range rels.Rels {
+			if rel.ID != sh.ID {
+				continue
+			}
+			if strings.HasPrefix(rel.Target, "/") {
+				return strings.TrimPrefix(rel.Target, "/"), nil
+			}
+			return path.Join(path.Dir(workbook), rel.Target), nil
+		}
+	}
+	return "", fmt.Errorf("worksheet %q not found", sheet)
+}
+
+// decodeZipXML unmarshals the XML part name of zr into v.
+func decodeZipXML(zr *zip.Reader, name string, v any) error {
+	f, err := zr.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return xml.NewDecoder(f).Decode(v)
+}
+
+// multiCloser closes every non-nil closer, returning the first error.
+type multiCloser []io.Closer
+
+func (m multiCloser) Close() error {
+	var first error
+	for _, c := range m {
+		if c == nil {
+			continue
+		}
+		if err := c.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
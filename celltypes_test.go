@@ -0,0 +1,149 @@
+package excelio
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestIsDateFormatCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"yyyy-mm-dd", true},
+		{"d/m/yy h:mm", true},
+		{"[$-409]mmmm d, yyyy", true},
+		{"[h]:mm:ss", true},
+		{"[mm]:ss", true},
+		{"hh:mm AM/PM", true},
+		{"[Red]dd.mm.yyyy;@", true},
+		{"0.00", false},
+		{"#,##0.00", false},
+		{"0.00E+00", false},
+		{"General", false},
+		{`0.0 "days"`, false},
+		{`#,##0\d`, false},
+		{"[Red]#,##0;[Blue]yyyy", false},
+		{"0_)", false},
+	}
+	for _, tt := range tests {
+		if got := isDateFormatCode(tt.code); got != tt.want {
+			t.Errorf("isDateFormatCode(%q) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
+
+type rawTypedRow struct {
+	Code   string    `excel:"Code"`
+	When   time.Time `excel:"When" fmt:"20060102"`
+	Amount float64   `excel:"Amount"`
+	Qty    int       `excel:"Qty"`
+}
+
+// rawTypedFixture builds a sheet whose When, Amount and Qty columns hold text
+// in row 2 and numbers in row 3; row 3's When has a date format.
+func rawTypedFixture(t *testing.T) []byte {
+	t.Helper()
+	f := excelize.NewFile()
+	defer f.Close()
+	sheet := f.GetSheetName(0)
+	_ = f.SetSheetRow(sheet, "A1", &[]any{"Code", "When", "Amount", "Qty"})
+	_ = f.SetSheetRow(sheet, "A2", &[]any{"text", "20240102", "1.234,5", "1.000"})
+	_ = f.SetSheetRow(sheet, "A3", &[]any{"number", 45292, 1234.5, 1000})
+	style, err := f.NewStyle(&excelize.Style{NumFmt: 14})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.SetCellStyle(sheet, "B3", "B3", style); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestRawValuesCellTypes(t *testing.T) {
+	data := rawTypedFixture(t)
+	path := filepath.Join(t.TempDir(), "typed.xlsx")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	opts := []Option{RawValues(), NumberLocale(LocaleDE)}
+	want := []rawTypedRow{
+		{Code: "text", When: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Amount: 1234.5, Qty: 1000},
+		{Code: "number", When: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Amount: 1234.5, Qty: 1000},
+	}
+
+	check := func(name string, got []rawTypedRow, errs []RowError, err error) {
+		t.Helper()
+		if err != nil || len(errs) > 0 {
+			t.Fatalf("%s: errs = %v, err = %v", name, errs, err)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("%s: got %d rows, want %d", name, len(got), len(want))
+		}
+		for i := range want {
+			g, w := got[i], want[i]
+			if g.Code != w.Code || !g.When.Equal(w.When) || g.Amount != w.Amount || g.Qty != w.Qty {
+				t.Errorf("%s: row %d = %+v, want %+v", name, i, g, w)
+			}
+		}
+	}
+
+	got, errs, err := Read[rawTypedRow](bytes.NewReader(data), opts...)
+	check("Read", got, errs, err)
+	got, errs, err = ReadFile[rawTypedRow](path, opts...)
+	check("ReadFile", got, errs, err)
+	got, errs, err = Read[rawTypedRow](bytes.NewReader(data), append(opts, AutoHeader(5))...)
+	check("AutoHeader", got, errs, err)
+
+	got = nil
+	res, err := ReadSheets(bytes.NewReader(data), SheetInto(&got, opts...))
+	if err == nil {
+		errs = res[0].Errors
+	}
+	check("ReadSheets", got, errs, err)
+}
+
+// The compressed bytes of an XLSX reader are only kept for RawValues.
+func TestOpenBookReaderKeepsDataForRawValues(t *testing.T) {
+	data := rawTypedFixture(t)
+	for _, raw := range []bool{false, true} {
+		b, err := openBookReader(bytes.NewReader(data), &Options{RawCellValues: raw})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if kept := b.(*xlsxBook).data != nil; kept != raw {
+			t.Errorf("RawCellValues=%v: data kept = %v", raw, kept)
+		}
+		_ = b.Close()
+	}
+}
+
+// A number that is not a valid serial still gets the field's layouts.
+func TestRawValuesNumberLayoutFallback(t *testing.T) {
+	f := excelize.NewFile()
+	sheet := f.GetSheetName(0)
+	_ = f.SetSheetRow(sheet, "A1", &[]any{"Code", "When"})
+	_ = f.SetSheetRow(sheet, "A2", &[]any{"n", 20240102})
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+
+	got, errs, err := Read[rawTypedRow](bytes.NewReader(buf.Bytes()), RawValues())
+	if err != nil || len(errs) > 0 || len(got) != 1 {
+		t.Fatalf("Read = %v, %v, %v", got, errs, err)
+	}
+	if want := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC); !got[0].When.Equal(want) {
+		t.Errorf("When = %v, want %v", got[0].When, want)
+	}
+}
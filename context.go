@@ -0,0 +1,167 @@
+package excelio
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+/* =========================================================
+ *  Context & Progress
+ * ========================================================= */
+
+// Progress is a snapshot of a running Read / Stream, passed to OnProgress.
+type Progress struct {
+	Rows     int           // Data rows processed so far (valid and invalid)
+	Errors   int           // RowErrors reported so far
+	ExcelRow int           // Physical row index of the last processed row
+	Elapsed  time.Duration // Time since the read started
+	Done     bool          // True for the final report after the last row
+}
+
+// ProgressFunc receives progress reports. It runs on the reading goroutine,
+// so it should return quickly.
+type ProgressFunc func(p Progress)
+
+// OnProgress reports progress every `every` data rows (and once more when the
+// sheet is finished). every <= 0 reports only the final summary.
+func OnProgress(every int, fn ProgressFunc) Option {
+	return func(o *Options) {
+		o.progressEvery = every
+		o.progress = fn
+	}
+}
+
+// ctxErr returns ctx.Err() if ctx is done, without blocking.
+func ctxErr(ctx context.Context) error {
+	if ctx == nil {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		return nil
+	}
+}
+
+// trackProgress wraps fn to count rows and errors and call Options.progress.
+// late wraps fn for the errors delivered after the last row (first rows of
+// duplicate keys, Aggregate checks), which count as errors but not as rows.
+// The returned finish function emits the final report.
+func trackProgress[T any](o *Options, fn rowFunc[T]) (wrapped, late rowFunc[T], finish func()) {
+	if o.progress == nil {
+		return fn, fn, func() {}
+	}
+	start := time.Now()
+	var p Progress
+	wrapped = func(rowIdx, logicalIdx int, obj T, rowErrs []RowError, ok bool) error {
+		p.Rows++
+		p.Errors += len(rowErrs)
+		p.ExcelRow = rowIdx
+		if err := fn(rowIdx, logicalIdx, obj, rowErrs, ok); err != nil {
+			return err
+		}
+		if o.progressEvery > 0 && p.Rows%o.progressEvery == 0 {
+			p.Elapsed = time.Since(start)
+			o.progress(p)
+		}
+		return nil
+	}
+	late = func(rowIdx, logicalIdx int, obj T, rowErrs []RowError, ok bool) error {
+		p.Errors += len(rowErrs)
+		return fn(rowIdx, logicalIdx, obj, rowErrs, ok)
+	}
+	finish = func() {
+		p.Elapsed = time.Since(start)
+		p.Done = true
+		o.progress(p)
+	}
+	return wrapped, late, finish
+}
+
+/* =========================================================
+ *  Public API: context-aware Read / Stream
+ * ========================================================= */
+
+// ReadFileContext is like ReadFile but stops with ctx.Err() as soon as ctx is
+// cancelled or its deadline passes.
+func ReadFileContext[T any](ctx context.Context, path string, opts ...Option) ([]T, []RowError, error) {
+	o := buildOptions(opts)
+	o.ctx = ctx
+
+	b, err := openBookFile(path, o)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer b.Close()
+
+	return readFromBook[T](b, o)
+}
+
+// ReadContext is like Read but stops with ctx.Err() as soon as ctx is
+// cancelled or its deadline passes.
+func ReadContext[T any](ctx context.Context, r io.Reader, opts ...Option) ([]T, []RowError, error) {
+	o := buildOptions(opts)
+	o.ctx = ctx
+
+	b, err := openBookReader(r, o)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer b.Close()
+
+	return readFromBook[T](b, o)
+}
+
+// StreamFileContext is like StreamFile but stops with ctx.Err() as soon as ctx
+// is cancelled or its deadline passes. Errors collected so far are returned,
+// and are not written back into the file.
+func StreamFileContext[T any](ctx context.Context, path string, opts ...Option) ([]RowError, error) {
+	o := buildOptions(opts)
+	o.ctx = ctx
+
+	if o.streamHandler == nil {
+		return nil, fmt.Errorf("excelio: OnStreamRow() or OnStreamBatch() is required for StreamFile")
+	}
+
+	b, err := openBookFile(path, o)
+	if err != nil {
+		return nil, err
+	}
+	allErrs, err := streamFromBook[T](b, o)
+	// Close before writing errors back into the same file.
+	_ = b.Close()
+	if err != nil {
+		return allErrs, err
+	}
+
+	// Auto-write errors back to the file if configured.
+	if (o.ErrorColumnIndex > 0 || len(o.SheetErrorColumns) > 0) && len(allErrs) > 0 {
+		if err := WriteErrors(path, allErrs, opts...); err != nil {
+			return allErrs, err
+		}
+	}
+
+	return allErrs, nil
+}
+
+// StreamContext is like Stream but stops with ctx.Err() as soon as ctx is
+// cancelled or its deadline passes. Errors collected so far are returned.
+func StreamContext[T any](ctx context.Context, r io.Reader, opts ...Option) ([]RowError, error) {
+	o := buildOptions(opts)
+	o.ctx = ctx
+
+	if o.streamHandler == nil {
+		return nil, fmt.Errorf("excelio: OnStreamRow() or OnStreamBatch() is required for Stream")
+	}
+
+	b, err := openBookReader(r, o)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Close()
+
+	return streamFromBook[T](b, o)
+}
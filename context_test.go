@@ -0,0 +1,47 @@
+package excelio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// StreamFile writes errors back with only a per-sheet error column set.
+func TestStreamFileSheetErrCol(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "items.xlsx")
+	if err := os.WriteFile(path, concFixture(t, FormatXLSX), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sheet := f.GetSheetName(0)
+	_ = f.Close()
+
+	errs, err := StreamFile[concItem](path, SheetErrCol(sheet, 3),
+		OnStreamRow(func(rowIdx, logicalIdx int, obj *concItem, rowErrs []RowError) error { return nil }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) == 0 {
+		t.Fatal("no row errors")
+	}
+
+	f, err = excelize.OpenFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	for _, e := range errs {
+		cell, _ := excelize.CoordinatesToCellName(3, e.ExcelRowIndex)
+		if v, _ := f.GetCellValue(sheet, cell); v == "" {
+			t.Errorf("row %d: no error written to %s", e.ExcelRowIndex, cell)
+		}
+	}
+	if v, _ := f.GetCellValue(sheet, "C3"); v != "" {
+		t.Errorf("valid row 3 got error %q", v)
+	}
+}
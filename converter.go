@@ -0,0 +1,178 @@
+package excelio
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+/* =========================================================
+ *  Custom Type Converters
+ * ========================================================= */
+
+// converter converts between raw cell text and a custom Go type.
+// Either side may be nil, in which case the built-in conversion is used.
+type converter struct {
+	decode func(raw string) (any, error)
+	encode func(v any) (any, error)
+}
+
+var globalConverters sync.Map // map[reflect.Type]converter
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	scannerType         = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	valuerType          = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+)
+
+// newConverter wraps typed decode/encode functions into a type-erased converter.
+func newConverter[T any](decode func(raw string) (T, error), encode func(v T) (any, error)) (reflect.Type, converter) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	var c converter
+	if decode != nil {
+		c.decode = func(raw string) (any, error) { return decode(raw) }
+	}
+	if encode != nil {
+		c.encode = func(v any) (any, error) { return encode(v.(T)) }
+	}
+	return t, c
+}
+
+// RegisterConverter registers a process-wide converter for type T, used by every
+// Read/Stream/Write call. decode parses the raw cell text into T; encode turns
+// a T into a cell value (string, number, bool or time.Time). Either function
+// may be nil to keep the default behavior for that direction.
+//
+// Converters registered with WithConverter take precedence over global ones.
+//
+//	excelio.RegisterConverter(
+//	    func(s string) (decimal.Decimal, error) { return decimal.NewFromString(s) },
+//	    func(d decimal.Decimal) (any, error) { return d.String(), nil },
+//	)
+func RegisterConverter[T any](decode func(raw string) (T, error), encode func(v T) (any, error)) {
+	t, c := newConverter(decode, encode)
+	globalConverters.Store(t, c)
+}
+
+// WithConverter registers a converter for type T for a single Read/Stream/Write call.
+// See RegisterConverter for the meaning of decode and encode.
+func WithConverter[T any](decode func(raw string) (T, error), encode func(v T) (any, error)) Option {
+	return func(o *Options) {
+		t, c := newConverter(decode, encode)
+		if o.converters == nil {
+			o.converters = make(map[reflect.Type]converter)
+		}
+		o.converters[t] = c
+	}
+}
+
+// lookupConverter returns the converter for t, checking Options first and then
+// the global registry.
+func lookupConverter(t reflect.Type, o *Options) (converter, bool) {
+	if o != nil && o.converters != nil {
+		if c, ok := o.converters[t]; ok {
+			return c, true
+		}
+	}
+	if v, ok := globalConverters.Load(t); ok {
+		return v.(converter), true
+	}
+	return converter{}, false
+}
+
+// decodeCustom handles registered converters and the standard decoding
+// interfaces (encoding.TextUnmarshaler, sql.Scanner).
+// It reports whether the field type was handled.
+func decodeCustom(field reflect.Value, raw string, o *Options) (bool, error) {
+	t := field.Type()
+	if c, ok := lookupConverter(t, o); ok && c.decode != nil {
+		val, err := c.decode(raw)
+		if err != nil {
+			return true, err
+		}
+		rv := reflect.ValueOf(val)
+		if !rv.IsValid() {
+			field.Set(reflect.Zero(t))
+			return true, nil
+		}
+		if !rv.Type().AssignableTo(t) {
+			return true, fmt.Errorf("converter for %s returned %s", t, rv.Type())
+		}
+		field.Set(rv)
+		return true, nil
+	}
+
+	// time.Time implements TextUnmarshaler but has its own parsing rules;
+	// sql.Null* types are converted through their value (see convertAndSet).
+	if t == timeType || isSQLNull(t) || !field.CanAddr() {
+		return false, nil
+	}
+	ptr := field.Addr()
+	if ptr.Type().Implements(textUnmarshalerType) {
+		return true, ptr.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(strings.TrimSpace(raw)))
+	}
+	if ptr.Type().Implements(scannerType) {
+		return true, ptr.Interface().(sql.Scanner).Scan(strings.TrimSpace(raw))
+	}
+	return false, nil
+}
+
+// encodeCustom handles registered converters and the standard encoding
+// interfaces (encoding.TextMarshaler, driver.Valuer) for a non-pointer value.
+// It reports whether the value type was handled.
+func encodeCustom(v reflect.Value, fm *fieldMeta, o *Options) (any, bool, error) {
+	t := v.Type()
+	if c, ok := lookupConverter(t, o); ok && c.encode != nil {
+		out, err := c.encode(v.Interface())
+		return out, true, err
+	}
+	if t == timeType || isSQLNull(t) {
+		return nil, false, nil
+	}
+
+	iv, ok := interfaceOf(v, textMarshalerType)
+	if ok {
+		b, err := iv.(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, true, err
+		}
+		return string(b), true, nil
+	}
+
+	iv, ok = interfaceOf(v, valuerType)
+	if ok {
+		dv, err := iv.(driver.Valuer).Value()
+		if err != nil {
+			return nil, true, err
+		}
+		switch x := dv.(type) {
+		case nil:
+			return "", true, nil
+		case []byte:
+			return string(x), true, nil
+		case time.Time:
+			out, err := valueToCell(reflect.ValueOf(x), fm, o)
+			return out, true, err
+		}
+		return dv, true, nil
+	}
+	return nil, false, nil
+}
+
+// interfaceOf returns v (or its address, when addressable) as an iface value
+// if either implements iface.
+func interfaceOf(v reflect.Value, iface reflect.Type) (any, bool) {
+	if v.Type().Implements(iface) {
+		return v.Interface(), true
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(iface) {
+		return v.Addr().Interface(), true
+	}
+	return nil, false
+}
@@ -0,0 +1,464 @@
+package excelio
+
+import (
+	"fmt"
+	"hash/maphash"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/* =========================================================
+ *  Cross-row rules: uniqueness, lookups & aggregates
+ * ========================================================= */
+
+// Stats summarizes a numeric field over the valid rows of a sheet (see Aggregate).
+type Stats struct {
+	Count    int     // Rows with a value; nil pointers and invalid sql.Null* are not counted
+	Sum      float64 // Sum of the values
+	Min, Max float64 // Smallest and largest value; 0 if Count is 0
+}
+
+// aggregate is a check registered with Aggregate.
+type aggregate struct {
+	field string
+	check func(Stats) error
+}
+
+// Lookup defines the set of keys named name for fields tagged `lookup:"name"`,
+// such as the customer codes an order sheet may reference:
+//
+//	type Order struct {
+//	    Customer string `excel:"Customer" lookup:"customers"`
+//	}
+//	orders, errs, err := excelio.ReadFile[Order]("orders.xlsx",
+//	    excelio.Lookup("customers", codes...))
+//
+// A non-empty value that is not in the set is a RowError. Values are compared
+// as text (fmt.Sprint of the field value); elements of slice fields are
+// checked one by one.
+func Lookup(name string, keys ...string) Option {
+	set := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		set[k] = struct{}{}
+	}
+	return LookupFunc(name, func(key string) bool {
+		_, ok := set[key]
+		return ok
+	})
+}
+
+// LookupFunc is like Lookup with a membership function, e.g. backed by a
+// database index when the set is too large to hold in memory. contains is
+// called on the reading goroutine, once per non-empty value.
+func LookupFunc(name string, contains func(key string) bool) Option {
+	return func(o *Options) {
+		if o.lookups == nil {
+			o.lookups = make(map[string]func(string) bool)
+		}
+		o.lookups[name] = contains
+	}
+}
+
+// Aggregate calls check with the Stats of the numeric field (a Go field path
+// such as "Amount" or "Billing.Total") over the valid rows, after the last row
+// of the sheet. An error from check is reported as a RowError for that column
+// with ExcelRowIndex 0, after all rows; stream handlers receive it with a nil
+// obj.
+func Aggregate(field string, check func(Stats) error) Option {
+	return func(o *Options) {
+		o.aggregates = append(o.aggregates, aggregate{field: field, check: check})
+	}
+}
+
+// SumEquals checks that field sums to want over the valid rows, e.g. against
+// the control total of a payment batch (see Aggregate).
+func SumEquals(field string, want float64) Option {
+	return Aggregate(field, func(s Stats) error {
+		if math.Abs(s.Sum-want) > 1e-9*math.Max(1, math.Abs(want)) {
+			return fmt.Errorf("sum is %s, expected %s", formatFloat(s.Sum), formatFloat(want))
+		}
+		return nil
+	})
+}
+
+// parseUniqueTag parses `unique:"true"` (the field alone must be unique) or
+// `unique:"order,..."` (the field is part of the composite keys named).
+//
+// Every later row repeating a key is rejected with a RowError. Read, ReadFile
+// and SheetInto without a handler also report the first row (it stays in the
+// result); Stream, OnStreamBatch and Rows cannot, because that row was
+// already delivered as valid when the duplicate is found.
+func parseUniqueTag(tag string) []string {
+	switch strings.ToLower(strings.TrimSpace(tag)) {
+	case "", "false", "0":
+		return nil
+	case "true", "1":
+		return []string{""}
+	}
+	return splitAndTrim(tag)
+}
+
+// rowRules holds the state of the cross-row rules of one sheet scan.
+// Its methods run on the goroutine that delivers rows, in delivery order.
+type rowRules struct {
+	uniques []*uniqueKey
+	lookups []lookupRule
+	aggs    []*aggState
+
+	// Buffered reads only: errors for the first rows of duplicate keys, which
+	// were delivered as valid before the duplicate was seen.
+	reportFirst bool
+	firstDups   []RowError
+}
+
+// uniqueKey tracks the keys seen for one `unique` group. Keys are stored as
+// 128-bit hashes, so memory grows with the number of distinct keys but not
+// with their length, and rows themselves are never kept.
+type uniqueKey struct {
+	fields []*fieldMeta
+	seeds  [2]maphash.Seed
+	seen   map[[2]uint64]keyRow // key hash -> row of its first occurrence
+}
+
+// keyRow is the row where a unique key first appeared.
+type keyRow struct {
+	rowIdx, logicalIdx int
+	reported           bool // firstDups has an error for it
+}
+
+type lookupRule struct {
+	fm       *fieldMeta
+	name     string
+	contains func(string) bool
+}
+
+type aggState struct {
+	fm    *fieldMeta
+	check func(Stats) error
+	stats Stats
+}
+
+// newRowRules builds the cross-row rules of meta and o. It returns nil if
+// there are none, and an error for unknown lookups or aggregate fields.
+func newRowRules(meta *typeMeta, o *Options) (*rowRules, error) {
+	r := &rowRules{reportFirst: o.firstDuplicates}
+	groups := make(map[string]*uniqueKey)
+	for _, fm := range append(append([]*fieldMeta{}, meta.Fields...), meta.Multi...) {
+		if len(fm.Unique) > 0 && fm.Slice {
+			return nil, fmt.Errorf("excelio: field %s: unique is not supported on slice fields", fm.FieldName)
+		}
+		for _, name := range fm.Unique {
+			if name == "" {
+				r.uniques = append(r.uniques, newUniqueKey(fm))
+				continue
+			}
+			if g, ok := groups[name]; ok {
+				g.fields = append(g.fields, fm)
+				continue
+			}
+			groups[name] = newUniqueKey(fm)
+			r.uniques = append(r.uniques, groups[name])
+		}
+		if fm.Lookup != "" {
+			contains, ok := o.lookups[fm.Lookup]
+			if !ok {
+				return nil, fmt.Errorf("excelio: field %s: unknown lookup %q", fm.FieldName, fm.Lookup)
+			}
+			r.lookups = append(r.lookups, lookupRule{fm: fm, name: fm.Lookup, contains: contains})
+		}
+	}
+	for _, a := range o.aggregates {
+		fm := meta.FindFieldByName(a.field)
+		if fm == nil || fm.Slice || fm.Rest {
+			return nil, fmt.Errorf("excelio: aggregate: unknown field %q", a.field)
+		}
+		if !isNumberKind(numericType(fm.ValueType).Kind()) {
+			return nil, fmt.Errorf("excelio: aggregate: field %s is not numeric", fm.FieldName)
+		}
+		r.aggs = append(r.aggs, &aggState{fm: fm, check: a.check})
+	}
+	if len(r.uniques)+len(r.lookups)+len(r.aggs) == 0 {
+		return nil, nil
+	}
+	return r, nil
+}
+
+func newUniqueKey(fm *fieldMeta) *uniqueKey {
+	return &uniqueKey{
+		fields: []*fieldMeta{fm},
+		seeds:  [2]maphash.Seed{maphash.MakeSeed(), maphash.MakeSeed()},
+		seen:   make(map[[2]uint64]keyRow),
+	}
+}
+
+// checkRows wraps fn so that every valid row passes the cross-row rules of
+// sc first; a row that fails them is delivered as invalid with the rule errors.
+func checkRows[T any](sc *sheetScanner[T], fn rowFunc[T]) rowFunc[T] {
+	return func(rowIdx, logicalIdx int, obj T, rowErrs []RowError, ok bool) error {
+		if ok {
+			if errs := sc.rules.check(sc.ruleScope(), reflect.ValueOf(&obj).Elem(), rowIdx, logicalIdx); len(errs) > 0 {
+				var zero T
+				obj, rowErrs, ok = zero, errs, false
+			}
+		}
+		return fn(rowIdx, logicalIdx, obj, rowErrs, ok)
+	}
+}
+
+// check applies the lookups, then the unique keys to a valid row, and adds it
+// to the aggregates if it passes. A rejected row does not reserve its keys.
+func (r *rowRules) check(sc ruleScope, v reflect.Value, rowIdx, logicalIdx int) []RowError {
+	var errs []RowError
+	for _, l := range r.lookups {
+		errs = append(errs, l.check(sc, v, rowIdx, logicalIdx)...)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	hashes := make([][2]uint64, len(r.uniques))
+	checked := make([]bool, len(r.uniques))
+	for i, u := range r.uniques {
+		texts := make([]string, len(u.fields))
+		empty := true
+		for j, fm := range u.fields {
+			texts[j] = fieldText(v, fm)
+			empty = empty && texts[j] == ""
+		}
+		if empty {
+			continue
+		}
+		hashes[i], checked[i] = u.hash(texts), true
+		if first, dup := u.seen[hashes[i]]; dup {
+			names := make([]string, len(u.fields))
+			for j, fm := range u.fields {
+				names[j] = fm.FieldName
+			}
+			errs = append(errs, sc.ruleError(u.fields[0], rowIdx, logicalIdx, strings.Join(texts, ", "),
+				fmt.Errorf("duplicate %s %s, first in row %d", strings.Join(names, ", "), quoteList(texts), first.rowIdx)))
+			if r.reportFirst && !first.reported {
+				r.firstDups = append(r.firstDups, sc.ruleError(u.fields[0], first.rowIdx, first.logicalIdx, strings.Join(texts, ", "),
+					fmt.Errorf("duplicate %s %s, repeated in row %d", strings.Join(names, ", "), quoteList(texts), rowIdx)))
+				first.reported = true
+				u.seen[hashes[i]] = first
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	for i, u := range r.uniques {
+		if checked[i] {
+			u.seen[hashes[i]] = keyRow{rowIdx: rowIdx, logicalIdx: logicalIdx}
+		}
+	}
+
+	for _, a := range r.aggs {
+		a.add(v)
+	}
+	return nil
+}
+
+// finish runs the aggregate checks after the last row.
+func (r *rowRules) finish(sc ruleScope) []RowError {
+	var errs []RowError
+	for _, a := range r.aggs {
+		if err := a.check(a.stats); err != nil {
+			errs = append(errs, sc.ruleError(a.fm, 0, 0, formatFloat(a.stats.Sum), err))
+		}
+	}
+	return errs
+}
+
+// sortByRow orders errs by ExcelRowIndex, keeping Aggregate errors (row 0)
+// last. Buffered reads use it once first-row duplicate errors arrived late.
+func sortByRow(errs []RowError) {
+	sort.SliceStable(errs, func(i, j int) bool {
+		a, b := errs[i].ExcelRowIndex, errs[j].ExcelRowIndex
+		return a != 0 && (b == 0 || a < b)
+	})
+}
+
+// hash returns the 128-bit hash of a key made of texts.
+func (u *uniqueKey) hash(texts []string) [2]uint64 {
+	var out [2]uint64
+	var h maphash.Hash
+	for i, seed := range u.seeds {
+		h.SetSeed(seed)
+		for _, t := range texts {
+			h.WriteString(t)
+			h.WriteByte(0)
+		}
+		out[i] = h.Sum64()
+	}
+	return out
+}
+
+// check reports the values of the lookup field (or its elements) that are
+// not in the set.
+func (l lookupRule) check(sc ruleScope, v reflect.Value, rowIdx, logicalIdx int) []RowError {
+	fv, ok := fieldValue(v, l.fm)
+	if !ok {
+		return nil
+	}
+	var errs []RowError
+	lookup := func(ev reflect.Value, col int) {
+		key := valueText(ev)
+		if key != "" && !l.contains(key) {
+			errs = append(errs, sc.ruleErrorAt(l.fm, col, rowIdx, logicalIdx, key,
+				fmt.Errorf("%q not found in %s", key, l.name)))
+		}
+	}
+	if l.fm.Slice {
+		for i := 0; i < fv.Len(); i++ {
+			lookup(fv.Index(i), sc.multi.sliceColumn(l.fm, i))
+		}
+		return errs
+	}
+	lookup(fv, sc.column(l.fm))
+	return errs
+}
+
+// add adds the field value of a valid row to the stats.
+func (a *aggState) add(v reflect.Value) {
+	fv, ok := fieldValue(v, a.fm)
+	if !ok {
+		return
+	}
+	x, ok := floatValue(fv)
+	if !ok {
+		return
+	}
+	s := &a.stats
+	if s.Count == 0 || x < s.Min {
+		s.Min = x
+	}
+	if s.Count == 0 || x > s.Max {
+		s.Max = x
+	}
+	s.Count++
+	s.Sum += x
+}
+
+// ruleScope is the column binding of the scanned sheet, used to place rule errors.
+type ruleScope struct {
+	sheet         string
+	headerMap     map[int]string
+	fieldColIndex map[*fieldMeta]int
+	multi         *multiColumns
+}
+
+func (sc *sheetScanner[T]) ruleScope() ruleScope {
+	return ruleScope{sheet: sc.sheet, headerMap: sc.headerMap, fieldColIndex: sc.fieldColIndex, multi: sc.multi}
+}
+
+func (sc ruleScope) column(fm *fieldMeta) int {
+	if col, ok := sc.fieldColIndex[fm]; ok {
+		return col
+	}
+	return -1
+}
+
+// ruleError builds the RowError of a rule for the column of fm.
+func (sc ruleScope) ruleError(fm *fieldMeta, rowIdx, logicalIdx int, value string, err error) RowError {
+	return sc.ruleErrorAt(fm, sc.column(fm), rowIdx, logicalIdx, value, err)
+}
+
+// ruleErrorAt builds the RowError of a rule for column col (0-based, -1 = none).
+func (sc ruleScope) ruleErrorAt(fm *fieldMeta, col, rowIdx, logicalIdx int, value string, err error) RowError {
+	re := RowError{
+		Sheet:         sc.sheet,
+		ExcelRowIndex: rowIdx,
+		LogicalIndex:  logicalIdx,
+		Field:         fm.FieldName,
+		Value:         value,
+		Err:           err,
+	}
+	if len(fm.ColumnNames) > 0 {
+		re.Column = fm.ColumnNames[0]
+	}
+	if col >= 0 {
+		re.ColIndex = col + 1
+		re.ColLetter = colLetter(col)
+		if h, ok := sc.headerMap[col]; ok {
+			re.Column = h
+		}
+	}
+	return re
+}
+
+// fieldValue returns the field of fm in v, or false if a nil embedded
+// pointer is in the way.
+func fieldValue(v reflect.Value, fm *fieldMeta) (reflect.Value, bool) {
+	fv, err := v.FieldByIndexErr(fm.Index)
+	return fv, err == nil
+}
+
+// fieldText returns the text of the field of fm in v, "" if it is empty.
+func fieldText(v reflect.Value, fm *fieldMeta) string {
+	fv, ok := fieldValue(v, fm)
+	if !ok {
+		return ""
+	}
+	return valueText(fv)
+}
+
+// valueText formats a field value for key comparison: "" for nil pointers
+// and invalid sql.Null* values, fmt.Sprint otherwise.
+func valueText(v reflect.Value) string {
+	v, ok := presentValue(v)
+	if !ok {
+		return ""
+	}
+	if v.Kind() == reflect.String {
+		return v.String()
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// presentValue dereferences pointers and unwraps sql.Null* values, reporting
+// false for nil or invalid ones.
+func presentValue(v reflect.Value) (reflect.Value, bool) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return v, false
+		}
+		v = v.Elem()
+	}
+	if isSQLNull(v.Type()) {
+		return sqlNullValue(v)
+	}
+	return v, true
+}
+
+// numericType returns the value type of a sql.Null* type, or t itself.
+func numericType(t reflect.Type) reflect.Type {
+	if isSQLNull(t) {
+		return t.Field(0).Type
+	}
+	return t
+}
+
+// floatValue converts a numeric field value to float64.
+func floatValue(v reflect.Value) (float64, bool) {
+	v, ok := presentValue(v)
+	if !ok {
+		return 0, false
+	}
+	switch {
+	case v.CanInt():
+		return float64(v.Int()), true
+	case v.CanUint():
+		return float64(v.Uint()), true
+	case v.CanFloat():
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,97 @@
+package excelio
+
+import (
+	"strings"
+	"testing"
+)
+
+type uniqueRow struct {
+	Ref    string  `excel:"Ref" unique:"true"`
+	Amount float64 `excel:"Amount"`
+}
+
+func TestUniqueReportsEveryRow(t *testing.T) {
+	const src = "Ref,Amount\nA,1\nB,2\nA,3\nC,4\nA,5\n"
+	type want struct {
+		row, logical int
+		msg          string
+	}
+	check := func(name string, errs []RowError, wants []want) {
+		t.Helper()
+		if len(errs) != len(wants) {
+			t.Fatalf("%s: got %d errors %v, want %d", name, len(errs), errs, len(wants))
+		}
+		for i, w := range wants {
+			e := errs[i]
+			if e.ExcelRowIndex != w.row || e.LogicalIndex != w.logical || e.Column != "Ref" ||
+				!strings.Contains(e.Err.Error(), w.msg) {
+				t.Errorf("%s: error %d = row %d/%d %q, want row %d/%d %q",
+					name, i, e.ExcelRowIndex, e.LogicalIndex, e.Err, w.row, w.logical, w.msg)
+			}
+		}
+	}
+
+	items, errs, err := Read[uniqueRow](strings.NewReader(src), Format(FormatCSV))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 3 || items[0].Ref != "A" || items[0].Amount != 1 {
+		t.Errorf("items = %+v, want A/1, B, C", items)
+	}
+	check("Read", errs, []want{
+		{2, 1, `duplicate Ref "A", repeated in row 4`},
+		{4, 3, `duplicate Ref "A", first in row 2`},
+		{6, 5, `duplicate Ref "A", first in row 2`},
+	})
+
+	// Streaming has delivered row 2 before the duplicate is seen.
+	errs, err = Stream[uniqueRow](strings.NewReader(src), Format(FormatCSV),
+		OnStreamRow(func(rowIdx, logicalIdx int, obj *uniqueRow, rowErrs []RowError) error { return nil }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	check("Stream", errs, []want{
+		{4, 3, "first in row 2"},
+		{6, 5, "first in row 2"},
+	})
+}
+
+func TestProgressCountsRuleErrors(t *testing.T) {
+	const src = "Ref,Amount\nA,1\nA,2\nB,3\nA,4\nB,5\n"
+	for _, workers := range []int{0, 3} {
+		var last Progress
+		_, errs, err := Read[uniqueRow](strings.NewReader(src), Format(FormatCSV), Workers(workers),
+			SumEquals("Amount", 0), OnProgress(0, func(p Progress) { last = p }))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Rows 3, 5 and 6 repeat a key, rows 2 and 4 are first occurrences,
+		// and the sum check fails.
+		if len(errs) != 6 {
+			t.Fatalf("workers=%d: got %d errors %v, want 6", workers, len(errs), errs)
+		}
+		if !last.Done || last.Rows != 5 || last.Errors != len(errs) {
+			t.Errorf("workers=%d: progress = %+v, want 5 rows and %d errors", workers, last, len(errs))
+		}
+	}
+}
+
+// Rows yields Aggregate errors last, as a row with both indexes 0.
+func TestRowsAggregateRow(t *testing.T) {
+	const src = "Ref,Amount\nA,1\nB,2\n"
+	var got []Row[uniqueRow]
+	for row, err := range Rows[uniqueRow](strings.NewReader(src), Format(FormatCSV), SumEquals("Amount", 4)) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, row)
+	}
+	if len(got) != 3 || !got[0].OK() || !got[1].OK() {
+		t.Fatalf("rows = %+v, want 2 valid rows and the aggregate row", got)
+	}
+	last := got[2]
+	if last.ExcelRowIndex != 0 || last.LogicalIndex != 0 || len(last.Errors) != 1 ||
+		!strings.Contains(last.Errors[0].Err.Error(), "sum is 3, expected 4") {
+		t.Errorf("aggregate row = %+v", last)
+	}
+}
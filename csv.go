@@ -0,0 +1,514 @@
+package excelio
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+/* =========================================================
+ *  CSV / TSV: options
+ * ========================================================= */
+
+const utf8BOM = "\ufeff"
+
+// Format sets the file format explicitly (FormatXLSX, FormatCSV, FormatTSV).
+// By default the format is detected from the file extension or content.
+func Format(f FileFormat) Option {
+	return func(o *Options) { o.Format = f }
+}
+
+// Delimiter sets the CSV field delimiter (default ',' for CSV, '\t' for TSV).
+// If not set, CSV input is sniffed for ',', ';', '\t' or '|'.
+func Delimiter(r rune) Option {
+	return func(o *Options) { o.Delimiter = r }
+}
+
+// Quote sets the CSV quote character (default '"').
+func Quote(r rune) Option {
+	return func(o *Options) { o.QuoteChar = r }
+}
+
+// BOM writes a UTF-8 byte order mark at the start of CSV/TSV output, which
+// helps Excel detect UTF-8 (e.g. for Thai text). A BOM on input is always
+// recognized and skipped.
+func BOM() Option {
+	return func(o *Options) { o.WriteBOM = true }
+}
+
+// Charset sets the text encoding of CSV/TSV input and output, for example
+// charmap.Windows874 or unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).
+// Default is UTF-8 (UTF-16 input with a BOM is detected automatically).
+func Charset(enc encoding.Encoding) Option {
+	return func(o *Options) { o.Charset = enc }
+}
+
+// csvDelimiter returns the configured delimiter for the given text format.
+func csvDelimiter(format FileFormat, o *Options) rune {
+	if o.Delimiter != 0 {
+		return o.Delimiter
+	}
+	if format == FormatTSV {
+		return '\t'
+	}
+	return ','
+}
+
+// csvQuote returns the configured quote character.
+func csvQuote(o *Options) rune {
+	if o.QuoteChar != 0 {
+		return o.QuoteChar
+	}
+	return '"'
+}
+
+// sniffDelimiter picks the most frequent candidate delimiter in the first line.
+func sniffDelimiter(head []byte) rune {
+	if i := bytes.IndexAny(head, "\r\n"); i >= 0 {
+		head = head[:i]
+	}
+	best, bestN := ',', 0
+	for _, c := range []rune{',', ';', '\t', '|'} {
+		if n := bytes.Count(head, []byte(string(c))); n > bestN {
+			best, bestN = c, n
+		}
+	}
+	return best
+}
+
+/* =========================================================
+ *  CSV / TSV: reading
+ * ========================================================= */
+
+// csvBook is a book backed by delimited text. It has a single, unnamed sheet
+// and can be iterated only once.
+type csvBook struct {
+	r      *bufio.Reader
+	closer io.Closer
+	comma  rune
+	quote  rune
+	used   bool
+}
+
+// newCSVBook wraps br (raw bytes) as a CSV/TSV source, applying the configured
+// or detected text encoding and delimiter.
+func newCSVBook(br *bufio.Reader, closer io.Closer, format FileFormat, o *Options) *csvBook {
+	var dec io.Reader = br
+	if o.Charset != nil {
+		dec = transform.NewReader(br, o.Charset.NewDecoder())
+	} else if head, _ := br.Peek(2); len(head) == 2 &&
+		((head[0] == 0xFF && head[1] == 0xFE) || (head[0] == 0xFE && head[1] == 0xFF)) {
+		dec = transform.NewReader(br, unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM).NewDecoder())
+	}
+	r := bufio.NewReader(dec)
+
+	comma := csvDelimiter(format, o)
+	if o.Delimiter == 0 && format != FormatTSV {
+		head, _ := r.Peek(4096)
+		comma = sniffDelimiter(head)
+	}
+	return &csvBook{r: r, closer: closer, comma: comma, quote: csvQuote(o)}
+}
+
+func (b *csvBook) rows(o *Options) (rowIterator, error) {
+	if b.used {
+		return nil, fmt.Errorf("excelio: csv source can only be read once")
+	}
+	b.used = true
+	return newCSVRows(b.r, b.comma, b.quote), nil
+}
+
+func (b *csvBook) Close() error {
+	c := b.closer
+	b.closer = nil
+	if c != nil {
+		return c.Close()
+	}
+	return nil
+}
+
+// readAll reads every record of the source (used by error write-back).
+func (b *csvBook) readAll() (records [][]string, hadBOM bool, err error) {
+	rows := newCSVRows(b.r, b.comma, b.quote)
+	for rows.Next() {
+		rec, err := rows.Columns()
+		if err != nil {
+			return nil, false, err
+		}
+		records = append(records, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+	return records, rows.hadBOM, nil
+}
+
+// csvRows is a rowIterator over delimited text with a configurable delimiter
+// and quote character. Quoted fields may contain delimiters, doubled quotes
+// and line breaks; stray quotes inside unquoted fields are kept as-is.
+type csvRows struct {
+	r      *bufio.Reader
+	comma  rune
+	quote  rune
+	cur    []string
+	err    error // error of the current record
+	fatal  error // read error of the source; ends the iteration
+	first  bool
+	hadBOM bool
+	field  strings.Builder
+}
+
+func newCSVRows(r *bufio.Reader, comma, quote rune) *csvRows {
+	return &csvRows{r: r, comma: comma, quote: quote, first: true}
+}
+
+func (c *csvRows) Next() bool {
+	if c.fatal != nil {
+		return false
+	}
+	if c.first {
+		c.first = false
+		if r, _, err := c.r.ReadRune(); err == nil {
+			if string(r) == utf8BOM {
+				c.hadBOM = true
+			} else {
+				_ = c.r.UnreadRune()
+			}
+		}
+	}
+	rec, err := c.readRecord()
+	if err == io.EOF {
+		return false
+	}
+	if err != nil && !errors.Is(err, errUnterminatedQuote) {
+		// The source itself failed (truncated upload, reset connection):
+		// reading on would fail again, so stop and report it via Err.
+		c.cur, c.err, c.fatal = nil, nil, err
+		return false
+	}
+	c.cur, c.err = rec, err
+	return true
+}
+
+func (c *csvRows) Columns() ([]string, error) { return c.cur, c.err }
+
+func (c *csvRows) Err() error { return c.fatal }
+
+func (c *csvRows) Close() error { return nil }
+
+var errUnterminatedQuote = errors.New("unterminated quoted field")
+
+// readRecord reads one logical record (which may span several lines when
+// quoted fields contain line breaks).
+func (c *csvRows) readRecord() ([]string, error) {
+	var rec []string
+	inQuotes, quoted, sawAny := false, false, false
+	c.field.Reset()
+
+	endField := func() {
+		rec = append(rec, c.field.String())
+		c.field.Reset()
+		quoted = false
+	}
+
+	for {
+		r, _, err := c.r.ReadRune()
+		if err == io.EOF {
+			if !sawAny {
+				return nil, io.EOF
+			}
+			endField()
+			if inQuotes {
+				return rec, errUnterminatedQuote
+			}
+			return rec, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		sawAny = true
+
+		if inQuotes {
+			if r == c.quote {
+				if next, _, err := c.r.ReadRune(); err == nil {
+					if next == c.quote {
+						c.field.WriteRune(c.quote)
+						continue
+					}
+					_ = c.r.UnreadRune()
+				}
+				inQuotes = false
+				continue
+			}
+			c.field.WriteRune(r)
+			continue
+		}
+
+		switch {
+		case r == c.quote && c.field.Len() == 0 && !quoted:
+			inQuotes, quoted = true, true
+		case r == c.comma:
+			endField()
+		case r == '\r':
+			if next, _, err := c.r.ReadRune(); err == nil && next != '\n' {
+				_ = c.r.UnreadRune()
+			}
+			endField()
+			return rec, nil
+		case r == '\n':
+			endField()
+			return rec, nil
+		default:
+			c.field.WriteRune(r)
+		}
+	}
+}
+
+/* =========================================================
+ *  CSV / TSV: writing
+ * ========================================================= */
+
+// csvWriter writes delimited records with a configurable delimiter and quote.
+type csvWriter struct {
+	w     *bufio.Writer
+	comma rune
+	quote rune
+}
+
+// writeRecord writes one record followed by CRLF, quoting fields as needed.
+func (cw *csvWriter) writeRecord(fields []string) error {
+	for i, f := range fields {
+		if i > 0 {
+			if _, err := cw.w.WriteRune(cw.comma); err != nil {
+				return err
+			}
+		}
+		if !cw.needsQuote(f) {
+			if _, err := cw.w.WriteString(f); err != nil {
+				return err
+			}
+			continue
+		}
+		q := string(cw.quote)
+		if _, err := cw.w.WriteString(q + strings.ReplaceAll(f, q, q+q) + q); err != nil {
+			return err
+		}
+	}
+	_, err := cw.w.WriteString("\r\n")
+	return err
+}
+
+func (cw *csvWriter) needsQuote(f string) bool {
+	if f == "" {
+		return false
+	}
+	return strings.ContainsRune(f, cw.comma) || strings.ContainsRune(f, cw.quote) ||
+		strings.ContainsAny(f, "\r\n") || f[0] == ' ' || f[len(f)-1] == ' '
+}
+
+// newCSVWriter prepares a csvWriter over w with the configured encoding and BOM.
+// The returned flush function must be called once all records are written.
+func newCSVWriter(w io.Writer, format FileFormat, o *Options, bom bool) (*csvWriter, func() error, error) {
+	var enc io.WriteCloser
+	if o.Charset != nil {
+		enc = transform.NewWriter(w, o.Charset.NewEncoder())
+		w = enc
+	}
+	bw := bufio.NewWriter(w)
+	if bom && o.Charset == nil {
+		if _, err := bw.WriteString(utf8BOM); err != nil {
+			return nil, nil, err
+		}
+	}
+	cw := &csvWriter{w: bw, comma: csvDelimiter(format, o), quote: csvQuote(o)}
+	flush := func() error {
+		if err := bw.Flush(); err != nil {
+			return err
+		}
+		if enc != nil {
+			return enc.Close()
+		}
+		return nil
+	}
+	return cw, flush, nil
+}
+
+// cellString formats a cell value produced by valueToCell as CSV text.
+func cellString(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case int64:
+		return strconv.FormatInt(x, 10)
+	case uint64:
+		return strconv.FormatUint(x, 10)
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(x), 'f', -1, 32)
+	case bool:
+		return strconv.FormatBool(x)
+	case time.Time:
+		return x.Format("2006-01-02 15:04:05")
+	}
+	return fmt.Sprint(v)
+}
+
+// csvSink is a rowSink writing delimited text. Rows are written in order and
+// gaps (e.g. between header and first data row) become empty lines.
+type csvSink struct {
+	cw      *csvWriter
+	flush   func() error
+	file    *os.File
+	nextRow int
+	rec     []string
+	decimal rune // NumberLocale decimal separator; 0 = '.'
+}
+
+// newCSVSink creates a csvSink writing to out, or to a new file at path.
+func newCSVSink(out io.Writer, path string, format FileFormat, o *Options) (*csvSink, error) {
+	var file *os.File
+	if out == nil {
+		f, err := os.Create(path)
+		if err != nil {
+			return nil, err
+		}
+		file, out = f, f
+	}
+	cw, flush, err := newCSVWriter(out, format, o, o.WriteBOM)
+	if err != nil {
+		if file != nil {
+			_ = file.Close()
+		}
+		return nil, err
+	}
+	s := &csvSink{cw: cw, flush: flush, file: file, nextRow: 1}
+	if o.NumberLocale != nil && o.NumberLocale.Decimal != '.' {
+		s.decimal = o.NumberLocale.Decimal
+	}
+	return s, nil
+}
+
+func (s *csvSink) setRow(row int, vals []any) error {
+	if row < s.nextRow {
+		return fmt.Errorf("excelio: csv rows must be written in order (row %d after %d)", row, s.nextRow-1)
+	}
+	for ; s.nextRow < row; s.nextRow++ {
+		if err := s.cw.writeRecord(nil); err != nil {
+			return err
+		}
+	}
+	s.rec = s.rec[:0]
+	for _, v := range vals {
+		text := cellString(v)
+		if s.decimal != 0 {
+			switch v.(type) {
+			case float64, float32:
+				text = strings.Replace(text, ".", string(s.decimal), 1)
+			}
+		}
+		s.rec = append(s.rec, text)
+	}
+	s.nextRow++
+	return s.cw.writeRecord(s.rec)
+}
+
+func (s *csvSink) close() error {
+	err := s.flush()
+	if s.file != nil {
+		if cerr := s.file.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+/* =========================================================
+ *  CSV / TSV: error write-back
+ * ========================================================= */
+
+// writeErrorsToRecords appends the error messages to the error column of the
+// matching records, growing rows as needed. Delimited text has a single sheet,
+// so RowError.Sheet is ignored.
+func writeErrorsToRecords(records [][]string, errs []RowError, errCol int) [][]string {
+	errColIdx := errCol - 1
+	for _, re := range errs {
+		if re.ExcelRowIndex <= 0 {
+			continue
+		}
+		for len(records) < re.ExcelRowIndex {
+			records = append(records, nil)
+		}
+		rec := records[re.ExcelRowIndex-1]
+		for len(rec) <= errColIdx {
+			rec = append(rec, "")
+		}
+		msg := re.Err.Error()
+		if rec[errColIdx] != "" {
+			msg = rec[errColIdx] + "\n" + msg
+		}
+		rec[errColIdx] = msg
+		records[re.ExcelRowIndex-1] = rec
+	}
+	return records
+}
+
+// writeErrorsToCSV reads all records of b, adds the error messages and writes
+// the result to w (or back to path if w is nil), keeping the source delimiter
+// and byte order mark.
+func writeErrorsToCSV(b *csvBook, errs []RowError, o *Options, w io.Writer, path string) error {
+	errCol := errorColumnFor("", o)
+	if errCol <= 0 {
+		return fmt.Errorf("excelio: no error column configured for CSV/TSV source; use ErrCol()")
+	}
+	records, hadBOM, err := b.readAll()
+	if err != nil {
+		return err
+	}
+	if err := b.Close(); err != nil {
+		return err
+	}
+	records = writeErrorsToRecords(records, errs, errCol)
+
+	oo := *o
+	oo.Delimiter = b.comma
+
+	if w != nil {
+		return writeRecords(w, records, &oo, hadBOM || o.WriteBOM)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := writeRecords(file, records, &oo, hadBOM || o.WriteBOM); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// writeRecords writes all records to w as delimited text.
+func writeRecords(w io.Writer, records [][]string, o *Options, bom bool) error {
+	cw, flush, err := newCSVWriter(w, FormatCSV, o, bom)
+	if err != nil {
+		return err
+	}
+	for _, rec := range records {
+		if err := cw.writeRecord(rec); err != nil {
+			return err
+		}
+	}
+	return flush()
+}
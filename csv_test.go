@@ -0,0 +1,121 @@
+package excelio
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+var errBoom = errors.New("boom")
+
+func TestWriteErrorsToCSVErrorColumn(t *testing.T) {
+	src := "Code,Qty\nA,1\nB,x\n"
+	errs := []RowError{{ExcelRowIndex: 3, Err: errors.New("invalid Qty")}}
+
+	var buf bytes.Buffer
+	if err := WriteErrorsTo(&buf, strings.NewReader(src), errs, Format(FormatCSV), ErrCol(3)); err != nil {
+		t.Fatal(err)
+	}
+	if want := "Code,Qty\r\nA,1\r\nB,x,invalid Qty\r\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	// SheetErrCol alone names no column of a CSV source.
+	buf.Reset()
+	err := WriteErrorsTo(&buf, strings.NewReader(src), errs, Format(FormatCSV), SheetErrCol("Lines", 3))
+	if err == nil || !strings.Contains(err.Error(), "no error column") {
+		t.Fatalf("err = %v, want no error column", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("wrote %q without an error column", buf.String())
+	}
+}
+
+func TestCSVRowsReadRecord(t *testing.T) {
+	tests := []struct {
+		name  string
+		in    string
+		comma rune
+		quote rune
+		want  [][]string
+		err   error // error of the last record
+		fatal error // source error after in; ends the iteration
+	}{
+		{name: "plain", in: "a,b,c\n1,2,3\n", want: [][]string{{"a", "b", "c"}, {"1", "2", "3"}}},
+		{name: "no trailing newline", in: "a,b\n1,2", want: [][]string{{"a", "b"}, {"1", "2"}}},
+		{name: "CRLF and lone CR", in: "a,b\r\n1,2\r3,4\r\n", want: [][]string{{"a", "b"}, {"1", "2"}, {"3", "4"}}},
+		{name: "empty fields", in: ",,\n\n", want: [][]string{{"", "", ""}, {""}}},
+		{name: "quoted delimiter", in: `"a,b",c` + "\n", want: [][]string{{"a,b", "c"}}},
+		{name: "doubled quote", in: `"say ""hi""",x` + "\n", want: [][]string{{`say "hi"`, "x"}}},
+		{name: "line break in quotes", in: "\"line 1\nline 2\",x\r\ny,z\n", want: [][]string{{"line 1\nline 2", "x"}, {"y", "z"}}},
+		{name: "stray quote kept", in: `ab"c,d` + "\n", want: [][]string{{`ab"c`, "d"}}},
+		{name: "semicolon", in: "a;b,c\n", comma: ';', want: [][]string{{"a", "b,c"}}},
+		{name: "tab", in: "a\tb\n", comma: '\t', want: [][]string{{"a", "b"}}},
+		{name: "single quote", in: "'a;b';c\n", comma: ';', quote: '\'', want: [][]string{{"a;b", "c"}}},
+		{name: "unicode", in: "ชื่อ,ราคา\nกาแฟ,฿45\n", want: [][]string{{"ชื่อ", "ราคา"}, {"กาแฟ", "฿45"}}},
+		{name: "unterminated quote", in: "a,\"bc\n", want: [][]string{{"a", "bc\n"}}, err: errUnterminatedQuote},
+		{name: "source error", in: "a,b\n1,", want: [][]string{{"a", "b"}}, fatal: errBoom},
+		{name: "source error in quotes", in: "a\n\"b", want: [][]string{{"a"}}, fatal: errBoom},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comma, quote := tt.comma, tt.quote
+			if comma == 0 {
+				comma = ','
+			}
+			if quote == 0 {
+				quote = '"'
+			}
+			var src io.Reader = strings.NewReader(tt.in)
+			if tt.fatal != nil {
+				src = io.MultiReader(src, iotest.ErrReader(tt.fatal))
+			}
+			rows := newCSVRows(bufio.NewReader(src), comma, quote)
+			var got [][]string
+			var lastErr error
+			for rows.Next() {
+				cols, err := rows.Columns()
+				got = append(got, cols)
+				lastErr = err
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("records = %q, want %q", got, tt.want)
+			}
+			if !errors.Is(lastErr, tt.err) {
+				t.Errorf("err = %v, want %v", lastErr, tt.err)
+			}
+			if err := rows.Err(); !errors.Is(err, tt.fatal) {
+				t.Errorf("Err() = %v, want %v", err, tt.fatal)
+			}
+			if rows.Next() {
+				t.Error("Next() = true after the end")
+			}
+		})
+	}
+}
+
+// A failing source ends Read with the error instead of a row error per retry.
+func TestReadCSVSourceError(t *testing.T) {
+	src := io.MultiReader(strings.NewReader("Code,Qty\nA,1\n"), iotest.ErrReader(errBoom))
+	items, errs, err := Read[concItem](src, Format(FormatCSV))
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("Read = %v, %v, %v; want %v", items, errs, err, errBoom)
+	}
+}
+
+func TestCSVRowsBOM(t *testing.T) {
+	rows := newCSVRows(bufio.NewReader(strings.NewReader(utf8BOM+"Code\nA\n")), ',', '"')
+	var got []string
+	for rows.Next() {
+		cols, _ := rows.Columns()
+		got = append(got, cols...)
+	}
+	if !rows.hadBOM || !reflect.DeepEqual(got, []string{"Code", "A"}) {
+		t.Errorf("hadBOM = %v, records = %q", rows.hadBOM, got)
+	}
+}
@@ -0,0 +1,207 @@
+package excelio
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/* =========================================================
+ *  Date parsing options: location, layouts, era, ambiguity
+ * ========================================================= */
+
+// buddhistEraOffset is the difference between Buddhist-era and Gregorian years.
+const buddhistEraOffset = 543
+
+// defaultTimeLayouts are tried after RFC3339 when TimeLayouts is not set.
+// Day-first layouts win over US month-first ones.
+var defaultTimeLayouts = []string{
+	"2006-01-02",
+	"02/01/2006",
+	"02-01-2006",
+	"2006/01/02",
+	"02/01/2006 15:04",
+	"2006-01-02 15:04",
+	"02-01-2006 15:04",
+}
+
+// TimeLocation interprets date texts without a zone, and Excel serial dates,
+// as wall-clock times in loc (default UTC). Written times are converted to loc
+// before formatting.
+func TimeLocation(loc *time.Location) Option {
+	return func(o *Options) { o.TimeLocation = loc }
+}
+
+// TimeLayouts replaces the built-in list of date layouts tried after a field's
+// `fmt` layouts and RFC3339. Layouts are tried in order and the first that
+// parses wins, e.g. TimeLayouts("01/02/2006", "2006-01-02") for US dates.
+func TimeLayouts(layouts ...string) Option {
+	return func(o *Options) { o.TimeLayouts = layouts }
+}
+
+// BuddhistEra reads and writes 4-digit years of date texts in the Buddhist era
+// (Gregorian + 543), as used in Thailand: "05/03/2567" is 2024-03-05.
+// Excel serial dates are not affected.
+func BuddhistEra() Option {
+	return func(o *Options) { o.BuddhistEra = true }
+}
+
+// RejectAmbiguousDates makes a date text an error when it parses under more
+// than one day/month order with different results, e.g. "03/04/2024" with a
+// day-first layout, whose month-first reading is March 4. Texts such as
+// "13/04/2024", or with equal day and month, are accepted.
+func RejectAmbiguousDates() Option {
+	return func(o *Options) { o.RejectAmbiguousDates = true }
+}
+
+// splitTimeFormats splits a `fmt` tag into its alternative layouts.
+func splitTimeFormats(tag string) []string {
+	if strings.TrimSpace(tag) == "" {
+		return nil
+	}
+	parts := strings.Split(tag, "|")
+	out := parts[:0]
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// timeLayoutsFor returns the layouts tried after fm's `fmt` layouts and RFC3339.
+func timeLayoutsFor(o *Options) []string {
+	if o != nil && len(o.TimeLayouts) > 0 {
+		return o.TimeLayouts
+	}
+	return defaultTimeLayouts
+}
+
+// timeLocation returns the location for zone-less times.
+func timeLocation(o *Options) *time.Location {
+	if o != nil && o.TimeLocation != nil {
+		return o.TimeLocation
+	}
+	return time.UTC
+}
+
+// parseTimeLayout parses s with layout in the configured location and era.
+func parseTimeLayout(s, layout string, o *Options) (time.Time, error) {
+	if o != nil && o.BuddhistEra {
+		s = shiftYear(s, -buddhistEraOffset)
+	}
+	return time.ParseInLocation(layout, s, timeLocation(o))
+}
+
+// firstLayoutMatch returns the result of the first layout that parses s.
+// With RejectAmbiguousDates, a different result from a later layout or from
+// the day/month-swapped form of the matching layout is an error.
+func firstLayoutMatch(s string, layouts []string, o *Options) (time.Time, bool, error) {
+	for i, layout := range layouts {
+		t, err := parseTimeLayout(s, layout, o)
+		if err != nil {
+			continue
+		}
+		if o != nil && o.RejectAmbiguousDates {
+			others := layouts[i+1:]
+			if swapped, ok := swapDayMonth(layout); ok {
+				others = append([]string{swapped}, others...)
+			}
+			for _, other := range others {
+				if t2, err := parseTimeLayout(s, other, o); err == nil && !t2.Equal(t) {
+					return time.Time{}, true, fmt.Errorf("ambiguous date %q: %s or %s",
+						s, t.Format("2006-01-02"), t2.Format("2006-01-02"))
+				}
+			}
+		}
+		return t, true, nil
+	}
+	return time.Time{}, false, nil
+}
+
+// dayMonthSwap maps numeric day layout elements to month elements and back.
+var dayMonthSwap = map[string]string{"01": "02", "02": "01", "1": "2", "2": "1"}
+
+// swapDayMonth returns layout with its numeric day and month exchanged
+// ("02/01/2006" ↔ "01/02/2006"). Layouts that start with the year, such as
+// ISO "2006-01-02", have a single reading and are not swapped, nor are
+// layouts without both a numeric day and month.
+func swapDayMonth(layout string) (string, bool) {
+	var b strings.Builder
+	days, months, first := 0, 0, true
+	for i := 0; i < len(layout); {
+		j := i
+		for j < len(layout) && layout[j] >= '0' && layout[j] <= '9' {
+			j++
+		}
+		if j == i {
+			b.WriteByte(layout[i])
+			i++
+			continue
+		}
+		run := layout[i:j]
+		if first && run == "2006" {
+			return "", false
+		}
+		first = false
+		if to, ok := dayMonthSwap[run]; ok && (i == 0 || layout[i-1] != '_') {
+			if to == "01" || to == "1" {
+				days++
+			} else {
+				months++
+			}
+			run = to
+		}
+		b.WriteString(run)
+		i = j
+	}
+	return b.String(), days > 0 && months > 0
+}
+
+// shiftYear adds delta to the first standalone 4-digit number in s, which is
+// taken to be the year.
+func shiftYear(s string, delta int) string {
+	for i := 0; i < len(s); {
+		j := i
+		for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+			j++
+		}
+		if j == i {
+			i++
+			continue
+		}
+		if j-i == 4 {
+			y, _ := strconv.Atoi(s[i:j])
+			return s[:i] + strconv.Itoa(y+delta) + s[j:]
+		}
+		i = j
+	}
+	return s
+}
+
+// formatTime formats a time for writing with fm's first `fmt` layout.
+func formatTime(t time.Time, fm *fieldMeta, o *Options) string {
+	if o != nil && o.TimeLocation != nil {
+		t = t.In(o.TimeLocation)
+	}
+	layout := "2006-01-02 15:04:05"
+	if fm != nil && len(fm.TimeFormats) > 0 {
+		layout = fm.TimeFormats[0]
+	}
+	s := t.Format(layout)
+	if o != nil && o.BuddhistEra {
+		s = shiftYear(s, buddhistEraOffset)
+	}
+	return s
+}
+
+// inLocation reinterprets the wall clock of a UTC time in the configured location.
+func inLocation(t time.Time, o *Options) time.Time {
+	loc := timeLocation(o)
+	if loc == time.UTC {
+		return t
+	}
+	y, m, d := t.Date()
+	return time.Date(y, m, d, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+}
@@ -0,0 +1,131 @@
+package excelio
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSwapDayMonth(t *testing.T) {
+	tests := []struct {
+		layout string
+		want   string
+		ok     bool
+	}{
+		{"02/01/2006", "01/02/2006", true},
+		{"01/02/2006", "02/01/2006", true},
+		{"2/1/2006 15:04", "1/2/2006 15:04", true},
+		{"02-01-06", "01-02-06", true},
+		{"2006-01-02", "", false}, // year first: one reading
+		{"2006/01/02 15:04", "", false},
+		{"Jan 2, 2006", "", false}, // no numeric month
+		{"_2/01/2006", "", false},  // padded day is not swapped
+		{"15:04:05", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := swapDayMonth(tt.layout)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("swapDayMonth(%q) = %q, %v; want %q, %v", tt.layout, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestFirstLayoutMatch(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	dayFirst := []string{"02/01/2006"}
+	strict := &Options{RejectAmbiguousDates: true}
+	tests := []struct {
+		name    string
+		s       string
+		layouts []string
+		o       *Options
+		want    time.Time
+		match   bool
+		wantErr bool
+	}{
+		{name: "first layout wins", s: "03/04/2024", layouts: []string{"02/01/2006", "01/02/2006"}, want: day(2024, 4, 3), match: true},
+		{name: "later layout", s: "2024-04-03", layouts: []string{"02/01/2006", "2006-01-02"}, want: day(2024, 4, 3), match: true},
+		{name: "no match", s: "April 3", layouts: dayFirst},
+		{name: "ambiguous", s: "03/04/2024", layouts: dayFirst, o: strict, match: true, wantErr: true},
+		{name: "ambiguous with a later layout", s: "03/04/2024", layouts: []string{"2006-01-02", "02/01/2006", "01/02/2006"}, o: strict, match: true, wantErr: true},
+		{name: "day above 12", s: "13/04/2024", layouts: dayFirst, o: strict, want: day(2024, 4, 13), match: true},
+		{name: "day equals month", s: "04/04/2024", layouts: dayFirst, o: strict, want: day(2024, 4, 4), match: true},
+		{name: "ISO is never ambiguous", s: "2024-03-04", layouts: []string{"2006-01-02"}, o: strict, want: day(2024, 3, 4), match: true},
+		{name: "Buddhist era", s: "05/03/2567", layouts: dayFirst, o: &Options{BuddhistEra: true}, want: day(2024, 3, 5), match: true},
+	}
+	for _, tt := range tests {
+		got, match, err := firstLayoutMatch(tt.s, tt.layouts, tt.o)
+		if match != tt.match || (err != nil) != tt.wantErr || (err == nil && !got.Equal(tt.want)) {
+			t.Errorf("%s: firstLayoutMatch(%q) = %v, %v, %v; want %v, %v, err %v",
+				tt.name, tt.s, got, match, err, tt.want, tt.match, tt.wantErr)
+		}
+	}
+}
+
+type dateOptRow struct {
+	Name string    `excel:"Name"`
+	When time.Time `excel:"When" fmt:"02.01.2006|2006-01-02 15:04"`
+	Any  time.Time `excel:"Any"`
+}
+
+func TestDateOptions(t *testing.T) {
+	ict := time.FixedZone("ICT", 7*3600)
+	tests := []struct {
+		name      string
+		when, any string
+		opts      []Option
+		wantWhen  time.Time
+		wantAny   time.Time
+		wantErr   bool
+	}{
+		{name: "fmt first layout", when: "05.03.2024", any: "2024-03-05",
+			wantWhen: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), wantAny: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)},
+		{name: "fmt second layout", when: "2024-03-05 10:30", any: "05/03/2024",
+			wantWhen: time.Date(2024, 3, 5, 10, 30, 0, 0, time.UTC), wantAny: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)},
+		{name: "TimeLocation", when: "2024-03-05 10:30", any: "2024-03-05T10:30:00Z", opts: []Option{TimeLocation(ict)},
+			wantWhen: time.Date(2024, 3, 5, 10, 30, 0, 0, ict), wantAny: time.Date(2024, 3, 5, 10, 30, 0, 0, time.UTC)},
+		{name: "TimeLayouts", when: "05.03.2024", any: "03/05/2024", opts: []Option{TimeLayouts("01/02/2006")},
+			wantWhen: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), wantAny: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)},
+		{name: "RejectAmbiguousDates", when: "13.03.2024", any: "05/03/2024", opts: []Option{RejectAmbiguousDates()}, wantErr: true},
+		{name: "BuddhistEra", when: "05.03.2567", any: "2567-03-05", opts: []Option{BuddhistEra()},
+			wantWhen: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), wantAny: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := "Name,When,Any\nx," + tt.when + "," + tt.any + "\n"
+			items, errs, err := Read[dateOptRow](strings.NewReader(src), append(tt.opts, Format(FormatCSV))...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantErr {
+				if len(errs) != 1 || errs[0].Field != "Any" || !strings.Contains(errs[0].Err.Error(), "ambiguous") {
+					t.Errorf("errs = %v, want ambiguous Any", errs)
+				}
+				return
+			}
+			if len(errs) > 0 || len(items) != 1 {
+				t.Fatalf("items = %v, errs = %v", items, errs)
+			}
+			if got := items[0]; !got.When.Equal(tt.wantWhen) || got.When.Location().String() != tt.wantWhen.Location().String() ||
+				!got.Any.Equal(tt.wantAny) {
+				t.Errorf("When = %v, Any = %v; want %v, %v", got.When, got.Any, tt.wantWhen, tt.wantAny)
+			}
+		})
+	}
+}
+
+func TestBuddhistEraRoundTrip(t *testing.T) {
+	rows := []dateOptRow{{Name: "songkran", When: time.Date(2024, 4, 13, 0, 0, 0, 0, time.UTC)}}
+	var buf bytes.Buffer
+	if err := Write(&buf, rows, Format(FormatCSV), BuddhistEra()); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "songkran,13.04.2567,") {
+		t.Fatalf("written %q, want 13.04.2567", buf.String())
+	}
+	got, errs, err := Read[dateOptRow](&buf, Format(FormatCSV), BuddhistEra())
+	if err != nil || len(errs) > 0 || len(got) != 1 || !got[0].When.Equal(rows[0].When) {
+		t.Errorf("read back %v, %v, %v; want %v", got, errs, err, rows[0].When)
+	}
+}
@@ -0,0 +1,154 @@
+package excelio
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+/* =========================================================
+ *  Empty cells, defaults & nullable types
+ * ========================================================= */
+
+// EmptyRule decides what an empty cell does to its field (see EmptyPolicy).
+type EmptyRule uint8
+
+const (
+	// EmptySkip leaves the zero value: 0, "", nil pointers, invalid sql.Null*.
+	EmptySkip EmptyRule = iota
+	// EmptyZero sets the zero value as a present value: pointers point to a
+	// zero value and sql.Null* types become Valid.
+	EmptyZero
+	// EmptyDefault converts the field's `default` tag as if it were the cell
+	// text; fields without one are left alone.
+	EmptyDefault
+	// EmptyError reports a RowError, like `required:"true"`.
+	EmptyError
+)
+
+// emptyRules are the rule names accepted by the `empty` tag.
+var emptyRules = map[string]EmptyRule{
+	"skip":    EmptySkip,
+	"zero":    EmptyZero,
+	"default": EmptyDefault,
+	"error":   EmptyError,
+}
+
+// emptyKeepSpace is the `empty` tag flag that makes whitespace a value.
+const emptyKeepSpace = "keepspace"
+
+// EmptyPolicy sets the rule for empty cells of all fields. Per field, the
+// `empty` tag overrides it (`empty:"zero"`, `empty:"error"`, ...), as do
+// `required:"true"` (always EmptyError) and a `default` tag, which selects
+// EmptyDefault:
+//
+//	type Order struct {
+//	    Currency string  `excel:"Currency" default:"THB"`
+//	    Discount *int    `excel:"Discount" empty:"zero"`  // &0 when empty
+//	    Note     string  `excel:"Note" empty:"keepspace"` // "  " is kept
+//	}
+//
+// A column missing from the sheet counts as empty for EmptyZero and
+// EmptyDefault, but never raises EmptyError; use StrictHeaders for that.
+// Slice and rest fields are not affected.
+func EmptyPolicy(rule EmptyRule) Option {
+	return func(o *Options) { o.EmptyPolicy = rule }
+}
+
+// KeepWhitespace treats cells that contain only whitespace as values rather
+// than empty cells, for all fields (per field: `empty:"keepspace"`). String
+// fields receive the text unchanged.
+func KeepWhitespace() Option {
+	return func(o *Options) { o.KeepWhitespace = true }
+}
+
+// parseEmptyTag parses `empty:"rule[,keepspace]"` into fm.
+func parseEmptyTag(fm *fieldMeta, tag string) error {
+	for _, part := range splitAndTrim(tag) {
+		if part == emptyKeepSpace {
+			fm.KeepSpace = true
+			continue
+		}
+		rule, ok := emptyRules[strings.ToLower(part)]
+		if !ok {
+			return fmt.Errorf("excelio: field %s: invalid empty tag %q", fm.FieldName, tag)
+		}
+		fm.Empty, fm.EmptyTag = rule, true
+	}
+	return nil
+}
+
+// isEmptyCell reports whether raw counts as an empty cell for fm.
+func isEmptyCell(raw string, fm *fieldMeta, o *Options) bool {
+	if fm.KeepSpace || (o != nil && o.KeepWhitespace) {
+		return raw == ""
+	}
+	return strings.TrimSpace(raw) == ""
+}
+
+// emptyRule returns the rule applied to empty cells of fm.
+func emptyRule(fm *fieldMeta, o *Options) EmptyRule {
+	switch {
+	case fm.Required:
+		return EmptyError
+	case fm.EmptyTag:
+		return fm.Empty
+	case fm.HasDefault:
+		return EmptyDefault
+	case o != nil:
+		return o.EmptyPolicy
+	}
+	return EmptySkip
+}
+
+// fillEmpty applies rule to the field of fm in v after an empty cell, or a
+// missing column if bound is false. Only EmptyError returns an error for the
+// cell itself; default conversion errors are wrapped.
+func fillEmpty(v reflect.Value, fm *fieldMeta, rule EmptyRule, bound bool, o *Options) error {
+	switch rule {
+	case EmptyZero:
+		field := fieldForSet(v, fm.Index)
+		if !field.CanSet() {
+			return nil
+		}
+		if field.Kind() == reflect.Ptr {
+			field.Set(reflect.New(field.Type().Elem()))
+			field = field.Elem()
+		}
+		if isSQLNull(field.Type()) {
+			field.Field(1).SetBool(true)
+		}
+	case EmptyDefault:
+		if !fm.HasDefault {
+			return nil
+		}
+		field := fieldForSet(v, fm.Index)
+		if !field.CanSet() {
+			return nil
+		}
+		if err := setFieldValue(field, fm, fm.Default, o, cellText); err != nil {
+			return fmt.Errorf("default %q: %w", fm.Default, err)
+		}
+	case EmptyError:
+		if bound {
+			return fmt.Errorf("value is empty")
+		}
+	}
+	return nil
+}
+
+// isSQLNull reports whether t is one of the database/sql nullable types
+// (sql.NullString, sql.NullInt64, ..., sql.Null[T]): a value and a Valid flag.
+func isSQLNull(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t.PkgPath() != "database/sql" ||
+		!strings.HasPrefix(t.Name(), "Null") || t.NumField() != 2 {
+		return false
+	}
+	valid := t.Field(1)
+	return valid.Name == "Valid" && valid.Type.Kind() == reflect.Bool
+}
+
+// sqlNullValue returns the value of a sql.Null* v and whether it is Valid.
+func sqlNullValue(v reflect.Value) (reflect.Value, bool) {
+	return v.Field(0), v.Field(1).Bool()
+}
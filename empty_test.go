@@ -0,0 +1,139 @@
+package excelio
+
+import (
+	"bytes"
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type emptyRow struct {
+	Code     string        `excel:"Code"`
+	Currency string        `excel:"Currency" default:"THB"`
+	Discount *int          `excel:"Discount" empty:"zero"`
+	Note     string        `excel:"Note" empty:"keepspace"`
+	Qty      sql.NullInt64 `excel:"Qty"`
+	Rate     *float64      `excel:"Rate"`
+	Seen     sql.NullBool  `excel:"Seen"`
+}
+
+func TestEmptyRules(t *testing.T) {
+	const header = "Code,Currency,Discount,Note,Qty,Rate,Seen\n"
+	zero, five, rate := 0, 5, 1.5
+	zeroRate := 0.0
+	tests := []struct {
+		name     string
+		src      string
+		opts     []Option
+		want     emptyRow
+		errs     []string // fields with a RowError
+		errorRow bool     // the row is rejected
+	}{
+		{
+			name: "values",
+			src:  header + "A,USD,5,hi,3,1.5,true\n",
+			want: emptyRow{Code: "A", Currency: "USD", Discount: &five, Note: "hi",
+				Qty: sql.NullInt64{Int64: 3, Valid: true}, Rate: &rate, Seen: sql.NullBool{Bool: true, Valid: true}},
+		},
+		{
+			name: "empty cells, EmptySkip",
+			src:  header + "A, ,,  , ,,\n",
+			want: emptyRow{Code: "A", Currency: "THB", Discount: &zero, Note: "  "},
+		},
+		{
+			name: "empty cells, EmptyZero",
+			src:  header + "A,,,,,,\n",
+			opts: []Option{EmptyPolicy(EmptyZero)},
+			want: emptyRow{Code: "A", Currency: "THB", Discount: &zero,
+				Qty: sql.NullInt64{Valid: true}, Rate: &zeroRate, Seen: sql.NullBool{Valid: true}},
+		},
+		{
+			name:     "empty cells, EmptyError",
+			src:      header + "A,,,,,,\n",
+			opts:     []Option{EmptyPolicy(EmptyError)},
+			errs:     []string{"Note", "Qty", "Rate", "Seen"},
+			errorRow: true,
+		},
+		{
+			name: "KeepWhitespace",
+			src:  header + "A,  ,,,,,\n",
+			opts: []Option{KeepWhitespace()},
+			want: emptyRow{Code: "A", Currency: "  ", Discount: &zero},
+		},
+		{
+			name: "missing columns, EmptySkip",
+			src:  "Code\nA\n",
+			want: emptyRow{Code: "A", Currency: "THB", Discount: &zero},
+		},
+		{
+			name: "missing columns, EmptyZero",
+			src:  "Code\nA\n",
+			opts: []Option{EmptyPolicy(EmptyZero)},
+			want: emptyRow{Code: "A", Currency: "THB", Discount: &zero,
+				Qty: sql.NullInt64{Valid: true}, Rate: &zeroRate, Seen: sql.NullBool{Valid: true}},
+		},
+		{
+			name: "missing columns, EmptyError",
+			src:  "Code\nA\n",
+			opts: []Option{EmptyPolicy(EmptyError)},
+			want: emptyRow{Code: "A", Currency: "THB", Discount: &zero},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, errs, err := Read[emptyRow](strings.NewReader(tt.src), append(tt.opts, Format(FormatCSV))...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var fields []string
+			for _, e := range errs {
+				fields = append(fields, e.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.errs) {
+				t.Errorf("errors on %q, want %q (%v)", fields, tt.errs, errs)
+			}
+			if tt.errorRow {
+				if len(items) != 0 {
+					t.Errorf("items = %+v, want none", items)
+				}
+				return
+			}
+			if len(items) != 1 || !reflect.DeepEqual(items[0], tt.want) {
+				t.Errorf("items = %+v, want %+v", items, tt.want)
+			}
+		})
+	}
+}
+
+type badDefaultRow struct {
+	Code  string `excel:"Code"`
+	Count int    `excel:"Count" default:"many"`
+}
+
+func TestEmptyDefaultError(t *testing.T) {
+	_, errs, err := Read[badDefaultRow](strings.NewReader("Code,Count\nA,\n"), Format(FormatCSV))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 1 || errs[0].Field != "Count" || !strings.Contains(errs[0].Err.Error(), `default "many"`) {
+		t.Errorf("errs = %v, want a default error on Count", errs)
+	}
+}
+
+func TestEmptyWriteNullable(t *testing.T) {
+	five := 5
+	rows := []emptyRow{
+		{Code: "A", Currency: "USD", Discount: &five, Qty: sql.NullInt64{Int64: 3, Valid: true},
+			Seen: sql.NullBool{Bool: false, Valid: true}},
+		{Code: "B", Qty: sql.NullInt64{Int64: 9}},
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, rows, Format(FormatCSV)); err != nil {
+		t.Fatal(err)
+	}
+	want := "Code,Currency,Discount,Note,Qty,Rate,Seen\r\nA,USD,5,,3,,false\r\nB,,,,,,\r\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,216 @@
+package excelio
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/xuri/excelize/v2"
+)
+
+/* =========================================================
+ *  Enum labels
+ * ========================================================= */
+
+// EnumLabel pairs a label shown in the spreadsheet with its Go value.
+type EnumLabel[T comparable] struct {
+	Label string
+	Value T
+}
+
+// enumDef maps the labels of one enum to values of type typ and back.
+type enumDef struct {
+	typ     reflect.Type
+	labels  []string                 // In declaration order (dropdowns, error messages)
+	byLabel map[string]reflect.Value // Lowercased label -> value
+	byValue map[any]string           // Value -> first label with that value
+}
+
+func newEnumDef(t reflect.Type) *enumDef {
+	return &enumDef{typ: t, byLabel: make(map[string]reflect.Value), byValue: make(map[any]string)}
+}
+
+// add registers label for v. Several labels may share a value; the first
+// one is written back.
+func (d *enumDef) add(label string, v reflect.Value) error {
+	label = strings.TrimSpace(label)
+	key := strings.ToLower(label)
+	if label == "" {
+		return fmt.Errorf("empty enum label")
+	}
+	if _, dup := d.byLabel[key]; dup {
+		return fmt.Errorf("duplicate enum label %q", label)
+	}
+	d.labels = append(d.labels, label)
+	d.byLabel[key] = v
+	if _, ok := d.byValue[v.Interface()]; !ok {
+		d.byValue[v.Interface()] = label
+	}
+	return nil
+}
+
+// set stores the value of the label raw (case-insensitive) in field.
+func (d *enumDef) set(field reflect.Value, raw string) error {
+	v, ok := d.byLabel[strings.ToLower(strings.TrimSpace(raw))]
+	if !ok {
+		return fmt.Errorf("unknown value %q, expected one of %s", strings.TrimSpace(raw), quoteList(d.labels))
+	}
+	field.Set(v)
+	return nil
+}
+
+// label returns the label written for v.
+func (d *enumDef) label(v reflect.Value) (string, error) {
+	if l, ok := d.byValue[v.Interface()]; ok {
+		return l, nil
+	}
+	return "", fmt.Errorf("value %v has no enum label", v.Interface())
+}
+
+var globalEnums sync.Map // map[reflect.Type]*enumDef
+
+// buildEnum creates the enumDef of type T from labels.
+func buildEnum[T comparable](labels []EnumLabel[T]) (reflect.Type, *enumDef, error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	d := newEnumDef(t)
+	for _, l := range labels {
+		if err := d.add(l.Label, reflect.ValueOf(l.Value)); err != nil {
+			return nil, nil, fmt.Errorf("excelio: enum %s: %w", t, err)
+		}
+	}
+	return t, d, nil
+}
+
+// RegisterEnum maps labels to values for every field of type T (usually a
+// named type such as `type Status int`) in all reads and writes, like an
+// `enum` tag on each of them. Labels are matched ignoring case and surrounding
+// spaces; the first label of a value is written. It panics on empty or
+// duplicate labels. Call it during initialization.
+//
+//	excelio.RegisterEnum(
+//	    excelio.EnumLabel[Status]{"Active", StatusActive},
+//	    excelio.EnumLabel[Status]{"Inactive", StatusInactive},
+//	)
+func RegisterEnum[T comparable](labels ...EnumLabel[T]) {
+	t, d, err := buildEnum(labels)
+	if err != nil {
+		panic(err)
+	}
+	globalEnums.Store(t, d)
+}
+
+// WithEnum is like RegisterEnum for a single Read/Stream/Write call; it takes
+// precedence over RegisterEnum. It panics on empty or duplicate labels.
+func WithEnum[T comparable](labels ...EnumLabel[T]) Option {
+	t, d, err := buildEnum(labels)
+	if err != nil {
+		panic(err)
+	}
+	return func(o *Options) {
+		if o.enums == nil {
+			o.enums = make(map[reflect.Type]*enumDef)
+		}
+		o.enums[t] = d
+	}
+}
+
+// EnumDropdowns adds a data-validation dropdown listing the labels to the
+// data cells of each enum column when writing XLSX (labels of one enum may
+// total at most 255 characters).
+func EnumDropdowns() Option {
+	return func(o *Options) { o.EnumDropdowns = true }
+}
+
+// parseEnumTag builds the enumDef of `enum:"Active=1,Inactive=0"` for values
+// of type t. A label without "=" is also its own value (for string fields).
+func parseEnumTag(fm *fieldMeta, tag string, t reflect.Type) error {
+	if !t.Comparable() {
+		return fmt.Errorf("excelio: field %s: enum needs a comparable type, not %s", fm.FieldName, t)
+	}
+	d := newEnumDef(t)
+	for _, part := range splitAndTrim(tag) {
+		label, value, ok := strings.Cut(part, "=")
+		if !ok {
+			value = label
+		}
+		v := reflect.New(t).Elem()
+		if err := convertKind(v, fm, strings.TrimSpace(value), nil, cellText); err != nil {
+			return fmt.Errorf("excelio: field %s: enum value %q: %w", fm.FieldName, value, err)
+		}
+		if err := d.add(label, v); err != nil {
+			return fmt.Errorf("excelio: field %s: %w", fm.FieldName, err)
+		}
+	}
+	fm.Enum = d
+	return nil
+}
+
+// enumFor returns the enum of values of type t for field fm: its `enum` tag,
+// then WithEnum, then RegisterEnum.
+func enumFor(t reflect.Type, fm *fieldMeta, o *Options) *enumDef {
+	if fm != nil && fm.Enum != nil && fm.Enum.typ == t {
+		return fm.Enum
+	}
+	if o != nil {
+		if d, ok := o.enums[t]; ok {
+			return d
+		}
+	}
+	if d, ok := globalEnums.Load(t); ok {
+		return d.(*enumDef)
+	}
+	return nil
+}
+
+// validationSink is implemented by sinks that support data validation (XLSX).
+type validationSink interface {
+	// addDropdown limits the cells of column col (0-based) from row fromRow
+	// down to the given labels.
+	addDropdown(col, fromRow int, labels []string) error
+}
+
+// addDropdown adds a list validation to sheet of f.
+func addDropdown(f *excelize.File, sheet string, col, fromRow int, labels []string) error {
+	dv := excelize.NewDataValidation(true)
+	dv.Sqref = fmt.Sprintf("%s%d:%s%d", colLetter(col), fromRow, colLetter(col), excelize.TotalRows)
+	if err := dv.SetDropList(labels); err != nil {
+		return err
+	}
+	return f.AddDataValidation(sheet, dv)
+}
+
+// addDropdowns adds the EnumDropdowns validations of the enum columns. It
+// must run before the first row is flushed.
+func (sw *StreamWriter[T]) addDropdowns() error {
+	vs, ok := sw.sink.(validationSink)
+	if !ok || !sw.opts.EnumDropdowns {
+		return nil
+	}
+	add := func(fm *fieldMeta, col int) error {
+		d := enumFor(fm.ValueType, fm, sw.opts)
+		if d == nil {
+			return nil
+		}
+		if err := vs.addDropdown(col, sw.curRow, d.labels); err != nil {
+			return fmt.Errorf("excelio: field %s: enum dropdown: %w", fm.FieldName, err)
+		}
+		return nil
+	}
+	for _, fm := range sw.fields {
+		if err := add(fm, sw.fieldColIndex[fm]); err != nil {
+			return err
+		}
+	}
+	for _, l := range sw.multi {
+		if l.fm.Rest {
+			continue
+		}
+		for _, c := range l.cols {
+			if err := add(l.fm, c); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
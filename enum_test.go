@@ -0,0 +1,122 @@
+package excelio
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type enumLevel int
+
+func init() {
+	RegisterEnum(
+		EnumLabel[enumLevel]{"Low", 1},
+		EnumLabel[enumLevel]{"High", 2},
+	)
+}
+
+type enumRow struct {
+	Name  string    `excel:"Name"`
+	State int       `excel:"State" enum:"Active=1,Inactive=0,On=1"`
+	Size  string    `excel:"Size" enum:"S,M,L"`
+	Level enumLevel `excel:"Level"`
+	Gate  enumLevel `excel:"Gate" enum:"Open=1,Shut=0"` // the tag wins over RegisterEnum
+}
+
+func TestEnumRead(t *testing.T) {
+	const src = "Name,State,Size,Level,Gate\n" +
+		"a, active ,M,HIGH,open\n" +
+		"b,On,S,Low,Shut\n" +
+		"c,Paused,XL,Medium,Ajar\n"
+	items, errs, err := Read[enumRow](strings.NewReader(src), Format(FormatCSV))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []enumRow{
+		{Name: "a", State: 1, Size: "M", Level: 2, Gate: 1},
+		{Name: "b", State: 1, Size: "S", Level: 1, Gate: 0},
+	}
+	if len(items) != len(want) || items[0] != want[0] || items[1] != want[1] {
+		t.Errorf("items = %+v, want %+v", items, want)
+	}
+
+	wantErrs := map[string]string{
+		"State": `unknown value "Paused", expected one of "Active", "Inactive", "On"`,
+		"Size":  `unknown value "XL", expected one of "S", "M", "L"`,
+		"Level": `unknown value "Medium", expected one of "Low", "High"`,
+		"Gate":  `unknown value "Ajar", expected one of "Open", "Shut"`,
+	}
+	if len(errs) != len(wantErrs) {
+		t.Fatalf("errs = %v, want %d", errs, len(wantErrs))
+	}
+	for _, e := range errs {
+		if e.ExcelRowIndex != 4 || e.Err.Error() != wantErrs[e.Field] {
+			t.Errorf("%s: row %d %q, want row 4 %q", e.Field, e.ExcelRowIndex, e.Err, wantErrs[e.Field])
+		}
+	}
+
+	// WithEnum replaces RegisterEnum for one call, but not the tag.
+	items, errs, err = Read[enumRow](strings.NewReader("Name,State,Size,Level,Gate\nd,Inactive,L,Lo,Open\n"),
+		Format(FormatCSV), WithEnum(EnumLabel[enumLevel]{"Lo", 1}))
+	if err != nil || len(errs) > 0 || len(items) != 1 || items[0].Level != 1 || items[0].Gate != 1 {
+		t.Errorf("WithEnum = %+v, %v, %v", items, errs, err)
+	}
+}
+
+func TestEnumWrite(t *testing.T) {
+	rows := []enumRow{
+		{Name: "a", State: 1, Size: "L", Level: 2, Gate: 0},
+		{Name: "b", State: 0, Size: "S", Level: 1, Gate: 1},
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, rows, Format(FormatCSV)); err != nil {
+		t.Fatal(err)
+	}
+	want := "Name,State,Size,Level,Gate\r\na,Active,L,High,Shut\r\nb,Inactive,S,Low,Open\r\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	// A value without a label cannot be written.
+	buf.Reset()
+	err := Write(&buf, []enumRow{{Name: "x", State: 7, Size: "S", Level: 1}}, Format(FormatCSV))
+	if err == nil || !strings.Contains(err.Error(), "no enum label") {
+		t.Errorf("err = %v, want no enum label", err)
+	}
+}
+
+func TestEnumDropdowns(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, []enumRow{{Name: "a", State: 1, Size: "M", Level: 1, Gate: 1}}, EnumDropdowns()); err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	dvs, err := f.GetDataValidations(f.GetSheetName(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]string)
+	for _, dv := range dvs {
+		got[dv.Sqref] = dv.Formula1
+	}
+	want := map[string]string{
+		"B2:B1048576": `"Active,Inactive,On"`,
+		"C2:C1048576": `"S,M,L"`,
+		"D2:D1048576": `"Low,High"`,
+		"E2:E1048576": `"Open,Shut"`,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("validations = %v, want %v", got, want)
+	}
+	for ref, list := range want {
+		if got[ref] != list {
+			t.Errorf("%s: list %s, want %s", ref, got[ref], list)
+		}
+	}
+}
@@ -1,9 +1,13 @@
 package excelio
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
@@ -11,6 +15,7 @@ import (
 
 	"github.com/go-playground/validator/v10"
 	"github.com/xuri/excelize/v2"
+	"golang.org/x/text/encoding"
 )
 
 /*
@@ -22,15 +27,59 @@ High-level features:
       - `excel:"Code"`    → match header text
       - `col:"2"`         → match column index (1-based)
       - `excelcol:"C"`    → match column letter
+      - `excelre:"^Price"` → match header by regular expression (or MatchHeader option)
+      - `excel:"-"`       → skip a field
+  - Repeated / dynamic columns:
+      - []T fields via `excel:"Size {n}"`, `excelre`, `excelcol:"D:H"` or a header list
+      - `excel:",rest"` map[string]string collects all unmapped columns
+  - Nested structs:
+      - Embedded structs are flattened
+      - Named struct fields are walked; `excelprefix:"Billing "` prefixes their headers
   - Type conversion for:
       - string, int*, uint*, float*, bool, time.Time (with custom format and Excel serial support)
       - Pointer types for the above
+      - Custom types via RegisterConverter / WithConverter
+      - Types implementing encoding.TextUnmarshaler / TextMarshaler
+        or sql.Scanner / driver.Valuer
+      - RawValues() reads stored XLSX values (full-precision numbers, date serials)
+        instead of formatted text; Excel error cells (#N/A, ...) become RowErrors
+      - Serial dates follow the workbook's 1900 / 1904 date system (Date1904)
+      - Dates: `fmt:"02/01/2006|2006-01-02"` alternatives, TimeLayouts, TimeLocation,
+        BuddhistEra and RejectAmbiguousDates
+      - Numbers: `numfmt:"#,##0.00"` tag and NumberLocale read grouped, accounting,
+        percent and currency text; numfmt applies Excel number formats on write
+      - Empty cells: `default:"THB"` and `empty:"zero|default|error|keepspace"` tags,
+        EmptyPolicy / KeepWhitespace options; sql.Null* types on read and write
+  - Transforms: `transform:"trim,upper,nospace"` rewrites cell text before conversion;
+    RegisterTransform / WithTransform add named transforms with optional write inverses
+  - Enums: `enum:"Active=1,Inactive=0"` maps labels to values and back;
+    RegisterEnum / WithEnum for named types, EnumDropdowns for XLSX dropdowns
   - Validation via go-playground/validator
+  - Cross-row rules: `unique:"true"` and composite `unique:"key"` tags, `lookup:"set"`
+    with Lookup / LookupFunc, and Aggregate / SumEquals checks after the last row
+  - Header matching: NormalizeHeaders (whitespace, punctuation, NFKC, underscores)
+    and FuzzyHeaders (similarity threshold) for imperfect headers
+  - AutoHeader(n) detects the header row below title blocks; OnHeader reports it
+  - Multi-row headers: HeaderRows(start, end) composes "Q1/Revenue" paths,
+    expanding merged header cells
+  - Header checks: StrictHeaders() fails on missing / unknown / duplicate headers;
+    InspectHeaders / InspectHeadersFile return a HeaderReport
+  - CSV / TSV support behind the same APIs:
+      - Format detected by extension (.csv, .tsv) or content (XLSX magic bytes)
+      - Format / Delimiter / Quote / BOM / Charset options
   - Streaming read APIs (low memory):
-      - StreamFile / Stream + OnStreamRow handler
+      - StreamFile / Stream + OnStreamRow handler (or OnStreamBatch for batches)
+      - RowsFile / Rows: iter.Seq2[Row[T], error] for range-over-func loops
+      - *Context variants honor cancellation/deadlines; OnProgress reports progress
+      - Workers(n) maps/validates rows in parallel (ordered, or Unordered())
+  - Multi-sheet reading:
+      - ReadSheetsFile / ReadSheets + SheetInto[T]: open once, map each sheet to its own type
+  - Multi-sheet writing:
+      - NewWorkbook / NewWorkbookFile + AddSheet[T] for one typed sheet per tab
   - Error tracking:
-      - RowError provides row/column/field/value/error details
-      - WriteErrors: write error messages back into an existing Excel file (by path)
+      - RowError provides sheet/row/column/field/value/error details
+      - WriteErrors: write error messages back into an existing Excel file (by path),
+        routing each error to its own sheet (SheetErrCol for per-sheet columns)
       - WriteErrorsTo: write a new Excel file with error messages to an io.Writer
 
 For lowest memory usage:
@@ -45,6 +94,7 @@ For simplicity:
 
 // RowError represents a detailed error for a specific row/column/field.
 type RowError struct {
+	Sheet         string // Sheet name (empty for CSV/TSV sources)
 	ExcelRowIndex int    // Physical row index in Excel (1-based)
 	LogicalIndex  int    // Logical data index (1,2,3,...) after skipping header/empty rows
 	ColIndex      int    // Column index (1-based)
@@ -60,6 +110,12 @@ type RowError struct {
 // If rowErrs is non-empty, obj may still be non-nil if you choose to treat soft errors.
 type RowHandler[T any] func(rowIdx, logicalIdx int, obj *T, rowErrs []RowError) error
 
+// BatchHandler is the callback used by OnStreamBatch.
+// batch holds the valid rows of the batch in sheet order, each with its
+// ExcelRowIndex and LogicalIndex; errs holds the errors of the invalid rows
+// covered by the same batch (with their row indices).
+type BatchHandler[T any] func(batch []Row[T], errs []RowError) error
+
 // GenericRowHandler is an internal, type-erased handler stored in Options.
 type GenericRowHandler func(rowIdx, logicalIdx int, obj any, rowErrs []RowError) error
 
@@ -78,26 +134,106 @@ type Options struct {
 
 	// Row layout:
 	HeaderRow    int // Header row index (1-based). 0 = no header
+	HeaderRowEnd int // Last header row for multi-row headers (see HeaderRows); 0 = HeaderRow
 	FirstDataRow int // First data row index (1-based)
 
+	// AutoHeaderRows > 0 detects the header row within the first N rows and
+	// overrides HeaderRow / FirstDataRow (see AutoHeader).
+	AutoHeaderRows int
+
+	// Header matching & validation (see headers.go / headermatch.go):
+	StrictHeaders  bool                         // Fail with *HeaderError on missing / unknown / duplicate headers
+	HeaderNorm     HeaderNorm                   // Normalizations applied before comparing headers; 0 = trim + case only
+	FuzzyThreshold float64                      // > 0 enables similarity matching for unmatched fields
+	headerMatchers map[string]func(string) bool // MatchHeader predicates by field name
+	onHeader       func(HeaderReport)           // OnHeader callback
+
 	// Row index mapper:
 	//   If not nil, logical index = RowIndexMapper(ExcelRowIndex, dataIdx)
 	//   Otherwise, logical index = dataIdx (1-based count of non-empty data rows).
 	RowIndexMapper func(excelRow int, dataIdx int) int
 
+	// Cell values:
+	RawCellValues bool // Read stored XLSX cell values instead of formatted text (see RawValues)
+	Date1904      bool // Serial dates count from 1904-01-01 (detected from XLSX workbooks on read)
+
+	// Dates (see dates.go):
+	TimeLocation         *time.Location // Location of zone-less date texts and serials; nil = UTC
+	TimeLayouts          []string       // Layouts tried after `fmt` and RFC3339; nil = built-in list
+	BuddhistEra          bool           // 4-digit years in date texts are Buddhist era (+543)
+	RejectAmbiguousDates bool           // Fail on texts readable with day and month swapped
+
+	// Numbers (see numbers.go):
+	NumberLocale *Locale // Separators of numeric text; nil = plain Go syntax unless a field has `numfmt`
+
+	// Empty cells (see empty.go):
+	EmptyPolicy    EmptyRule // Rule for empty cells of fields without their own; default EmptySkip
+	KeepWhitespace bool      // Whitespace-only cells are values, not empty
+
+	// Enums (see enum.go):
+	EnumDropdowns bool // Add a label dropdown to enum columns when writing XLSX
+
 	// Validation:
 	GoValidator *validator.Validate
 
+	// Custom type converters registered via WithConverter.
+	converters map[reflect.Type]converter
+
+	// Named transforms registered via WithTransform.
+	transforms map[string]Transform
+
+	// Enums registered via WithEnum, by value type.
+	enums map[reflect.Type]*enumDef
+
+	// Cross-row rules (see crossrow.go): Lookup sets by name and Aggregate checks.
+	lookups    map[string]func(string) bool
+	aggregates []aggregate
+
+	// Set by buffered reads: the first row of a duplicate key gets a RowError
+	// too, delivered after the last row (see scanSheet).
+	firstDuplicates bool
+
 	// Error column:
 	//   If > 0, WriteErrors / WriteErrorsTo / StreamFile can write error messages
 	//   into this 1-based column index.
 	ErrorColumnIndex int
 
+	// Per-sheet error columns (1-based), set via SheetErrCol. Sheets not listed
+	// use ErrorColumnIndex.
+	SheetErrorColumns map[string]int
+
+	// File format (see source.go / csv.go):
+	Format    FileFormat        // FormatAuto (default) detects by extension or content
+	Delimiter rune              // CSV/TSV field delimiter; 0 = default / sniffed
+	QuoteChar rune              // CSV/TSV quote character; 0 = '"'
+	WriteBOM  bool              // Write a UTF-8 BOM when writing CSV/TSV
+	Charset   encoding.Encoding // CSV/TSV text encoding; nil = UTF-8
+
 	// Internal cache:
 	sheetResolved string
 
 	// Internal streaming handler:
 	streamHandler GenericRowHandler
+	streamFlush   func() error // called once after the last row (OnStreamBatch)
+
+	// Parallel mapping (see parallel.go):
+	Workers   int  // Number of mapping goroutines; <= 1 maps on the reading goroutine
+	Unordered bool // Deliver rows as soon as they are mapped instead of in sheet order
+
+	// Internal cancellation & progress (see context.go):
+	ctx           context.Context
+	progress      ProgressFunc
+	progressEvery int
+}
+
+// buildOptions applies opts and the defaults to a fresh Options.
+func buildOptions(opts []Option) *Options {
+	var o Options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	applyDefaults(&o)
+	return &o
 }
 
 // applyDefaults fills in default values for unspecified options.
@@ -146,11 +282,52 @@ func StartRow(row int) Option {
 	return func(o *Options) { o.FirstDataRow = row }
 }
 
+// RawValues reads the values stored in XLSX cells instead of the text Excel
+// displays, so conversion does not depend on number formats:
+//
+//   - numbers keep full precision ("1234.5678", not "1,234.57"; "0.12", not "12%")
+//   - dates arrive as serial numbers and are converted for time.Time fields
+//   - booleans arrive as "1" / "0", formulas as their cached result
+//   - shared and inline strings are unchanged
+//
+// The cell types are read as well: only number and date cells are treated as
+// serials and stored numbers, so text cells still go through `fmt` layouts
+// and locale number parsing. Whole-number floats ("3.0000000001" is not one)
+// are accepted for integer fields. String fields receive the stored value
+// too. CSV/TSV are unaffected.
+func RawValues() Option {
+	return func(o *Options) { o.RawCellValues = true }
+}
+
+// Date1904 selects the 1904 date system, used by workbooks created with older
+// Mac versions of Excel, in which serial 0 is 1904-01-01 instead of 1900-01-00.
+//
+// On read, XLSX workbooks declare their date system and it is detected
+// automatically; use Date1904 to force it, e.g. for serials exported to CSV
+// from such a workbook. On write, the XLSX workbook is marked as 1904-based,
+// so dates written as native cells (e.g. time.Time returned by a converter)
+// are stored as 1904 serials.
+func Date1904() Option {
+	return func(o *Options) { o.Date1904 = true }
+}
+
 // ErrCol sets the 1-based error column index.
 func ErrCol(idx int) Option {
 	return func(o *Options) { o.ErrorColumnIndex = idx }
 }
 
+// SheetErrCol sets the 1-based error column for a specific sheet, used by
+// WriteErrors / WriteErrorsTo when errors from several sheets are written back
+// in one call. Sheets without their own column fall back to ErrCol(...).
+func SheetErrCol(sheet string, idx int) Option {
+	return func(o *Options) {
+		if o.SheetErrorColumns == nil {
+			o.SheetErrorColumns = make(map[string]int)
+		}
+		o.SheetErrorColumns[sheet] = idx
+	}
+}
+
 // UseValidator sets the go-playground/validator instance used for struct validation.
 func UseValidator(v *validator.Validate) Option {
 	return func(o *Options) { o.GoValidator = v }
@@ -177,35 +354,114 @@ func OnStreamRow[T any](h RowHandler[T]) Option {
 	}
 }
 
+// OnStreamBatch registers a batch handler for Stream / StreamFile, as an
+// alternative to OnStreamRow. Every `size` data rows, h is called with the
+// valid rows and the errors of the invalid rows in that span; the last,
+// possibly smaller batch is flushed at the end of the sheet, together with
+// any Aggregate errors (which do not count as rows). Returning an error from
+// h stops the stream, like OnStreamRow.
+//
+// Each call gets a freshly allocated batch slice, so h may keep it.
+func OnStreamBatch[T any](size int, h BatchHandler[T]) Option {
+	return func(o *Options) {
+		if h == nil {
+			return
+		}
+		if size <= 0 {
+			size = 1
+		}
+		var batch []Row[T]
+		var errs []RowError
+		n := 0 // data rows covered by the current batch
+
+		flush := func() error {
+			if n == 0 && len(errs) == 0 {
+				return nil
+			}
+			b, e := batch, errs
+			batch, errs, n = nil, nil, 0
+			return h(b, e)
+		}
+		o.streamHandler = func(rowIdx, logicalIdx int, obj any, rowErrs []RowError) error {
+			errs = append(errs, rowErrs...)
+			if rowIdx == 0 {
+				return nil // Aggregate errors after the last row
+			}
+			if p, ok := obj.(*T); ok && p != nil {
+				if batch == nil {
+					batch = make([]Row[T], 0, size)
+				}
+				batch = append(batch, Row[T]{Value: *p, ExcelRowIndex: rowIdx, LogicalIndex: logicalIdx, Errors: rowErrs})
+			}
+			n++
+			if n >= size {
+				return flush()
+			}
+			return nil
+		}
+		o.streamFlush = flush
+	}
+}
+
 /* =========================================================
  *  Type Metadata & Tags
  * ========================================================= */
 
 // fieldMeta stores mapping info for a single struct field.
 type fieldMeta struct {
-	Index        []int
-	FieldName    string
-	ColumnNames  []string // From `excel:"Code,Name,..."`
-	ColIndexTag  int      // From `col:"2"` (0-based). -1 = none
-	ColLetterTag string   // From `excelcol:"C"` (normalized uppercase)
-
-	Required   bool   // From tag `required:"true"` or `required:"1"`
-	TimeFormat string // From tag `fmt:"2006-01-02"`
-
-	// lastColIndex is the last column index used for this field in the current row.
-	// Used for mapping validator errors back to the column.
-	lastColIndex int
+	Index        []int          // Index path from the root struct, usable with FieldByIndex
+	FieldName    string         // Display name, e.g. "Code" or "Billing.Street" (embedded names omitted)
+	Path         string         // Full Go field path, e.g. "AuditFields.CreatedBy"; matches validator namespaces
+	ColumnNames  []string       // From `excel:"Code,Name,..."` (with any `excelprefix` applied)
+	ColIndexTag  int            // From `col:"2"` (0-based). -1 = none
+	ColLetterTag string         // From `excelcol:"C"` (normalized uppercase)
+	HeaderRe     *regexp.Regexp // From `excelre:"^Price \\(.*\\)"`; tried after exact header matches
+
+	// Multi-column fields (see multicol.go):
+	Slice    bool           // []T field spanning several columns
+	NameRe   *regexp.Regexp // From `excel:"Size {n}"`: matches a header, capturing n
+	NameTmpl string         // The `excel:"Size {n}"` template, used when writing
+	ColFrom  int            // From `excelcol:"D:H"` (0-based, inclusive). -1 = none
+	ColTo    int
+	Rest     bool // From `excel:",rest"` on a map[string]string field
+
+	Required    bool     // From tag `required:"true"` or `required:"1"`
+	TimeFormats []string // From tag `fmt:"02/01/2006|2006-01-02"`; the first is used on write
+	NumFmt      string   // From tag `numfmt:"#,##0.00"`: Excel number format used on write
+	Transforms  []string // From tag `transform:"trim,upper"` (see transform.go)
+	Enum        *enumDef // From tag `enum:"Active=1,Inactive=0"` (see enum.go)
+	Unique      []string // From tag `unique:"true"` ("") or `unique:"order"` (composite key names)
+	Lookup      string   // From tag `lookup:"customers"`: name of the Lookup set (see crossrow.go)
+
+	// ValueType is the type of one cell value: the field type without
+	// pointers, or the element type of a slice field.
+	ValueType reflect.Type
+
+	// Empty cells (see empty.go):
+	Default    string    // From tag `default:"THB"`
+	HasDefault bool      // The `default` tag is present (it may be "")
+	Empty      EmptyRule // From tag `empty:"zero"`
+	EmptyTag   bool      // The `empty` tag sets a rule
+	KeepSpace  bool      // From tag `empty:"keepspace"`: whitespace-only cells are values
 }
 
 // typeMeta stores metadata for a struct type.
+// It is built once per type by getTypeMeta, shared through metaCache and must be
+// treated as read-only afterwards: concurrent reads of the same type use the
+// same *typeMeta and *fieldMeta values. Per-read state (such as the resolved
+// column of each field) lives in the maps built by each read, never here.
 type typeMeta struct {
-	Fields        []*fieldMeta
+	Fields        []*fieldMeta          // Single-column fields
+	Multi         []*fieldMeta          // []T fields spanning several columns
+	Rest          *fieldMeta            // `excel:",rest"` map collecting unmapped columns
 	HeaderToField map[string]*fieldMeta // header text (lowercased) -> field
-	FieldByName   map[string]*fieldMeta // struct field name -> field
+	FieldByName   map[string]*fieldMeta // field path (fieldMeta.Path) -> field
 }
 
 var metaCache sync.Map // map[reflect.Type]*typeMeta
 
+var timeType = reflect.TypeOf(time.Time{})
+
 // splitAndTrim splits a comma-separated string and trims each part.
 func splitAndTrim(s string) []string {
 	if s == "" {
@@ -223,14 +479,17 @@ func splitAndTrim(s string) []string {
 }
 
 // getTypeMeta builds and caches metadata for a struct type T.
+// Embedded structs are flattened and untagged struct fields are walked as
+// nested blocks (see collectFields).
+// It is safe for concurrent use; the returned *typeMeta is immutable.
 func getTypeMeta(t reflect.Type) (*typeMeta, error) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
 	if v, ok := metaCache.Load(t); ok {
 		return v.(*typeMeta), nil
 	}
 
-	if t.Kind() == reflect.Ptr {
-		t = t.Elem()
-	}
 	if t.Kind() != reflect.Struct {
 		return nil, fmt.Errorf("excelio: type %s is not struct", t.String())
 	}
@@ -239,37 +498,142 @@ func getTypeMeta(t reflect.Type) (*typeMeta, error) {
 		HeaderToField: make(map[string]*fieldMeta),
 		FieldByName:   make(map[string]*fieldMeta),
 	}
+	if err := collectFields(m, t, nil, "", "", "", map[reflect.Type]bool{t: true}); err != nil {
+		return nil, err
+	}
+
+	// Keep a single canonical instance per type even if several goroutines
+	// built the metadata at the same time.
+	actual, _ := metaCache.LoadOrStore(t, m)
+	return actual.(*typeMeta), nil
+}
 
+// collectFields walks the fields of struct type t in declaration order and
+// appends every mapped field to m.
+//
+//   - index is the FieldByIndex path of t from the root struct
+//   - prefix is prepended to header names (from `excelprefix` tags)
+//   - namePrefix / pathPrefix build fieldMeta.FieldName / fieldMeta.Path
+//   - visiting guards against recursive types
+//
+// A struct (or pointer-to-struct) field without mapping tags is walked as a
+// nested block: embedded structs are flattened, named ones contribute their
+// name to the field path and may add a header prefix via `excelprefix:"..."`.
+// time.Time is always treated as a single value. Use `excel:"-"` to skip a field.
+// An invalid `excelre` pattern is returned as an error.
+func collectFields(m *typeMeta, t reflect.Type, index []int, prefix, namePrefix, pathPrefix string, visiting map[reflect.Type]bool) error {
 	numField := t.NumField()
 	for i := 0; i < numField; i++ {
 		f := t.Field(i)
-		// Skip unexported fields.
-		if f.PkgPath != "" {
+
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+
+		// Skip unexported fields; embedded unexported structs are still
+		// walked (same rule as encoding/json) as long as they are not pointers.
+		if f.PkgPath != "" && !(f.Anonymous && f.Type.Kind() == reflect.Struct) {
 			continue
 		}
 
 		excelTag := f.Tag.Get("excel")
 		colTag := f.Tag.Get("col")
 		excelColTag := f.Tag.Get("excelcol")
+		excelReTag := f.Tag.Get("excelre")
+		if excelTag == "-" {
+			continue
+		}
+
+		fieldIndex := make([]int, len(index)+1)
+		copy(fieldIndex, index)
+		fieldIndex[len(index)] = i
+
+		path := pathPrefix + f.Name
+		name := namePrefix + f.Name
+
+		// Nested / embedded struct block.
+		if excelTag == "" && colTag == "" && excelColTag == "" && excelReTag == "" &&
+			ft.Kind() == reflect.Struct && ft != timeType {
+			if visiting[ft] {
+				continue
+			}
+			childName := name + "."
+			if f.Anonymous {
+				childName = namePrefix
+			}
+			visiting[ft] = true
+			err := collectFields(m, ft, fieldIndex, prefix+f.Tag.Get("excelprefix"), childName, path+".", visiting)
+			delete(visiting, ft)
+			if err != nil {
+				return err
+			}
+			continue
+		}
 
 		// Only consider fields that have at least one mapping tag.
-		if excelTag == "" && colTag == "" && excelColTag == "" {
+		if excelTag == "" && colTag == "" && excelColTag == "" && excelReTag == "" {
 			continue
 		}
 
 		fm := &fieldMeta{
-			Index:       f.Index,
-			FieldName:   f.Name,
+			Index:       fieldIndex,
+			FieldName:   name,
+			Path:        path,
 			ColumnNames: splitAndTrim(excelTag),
 			Required:    f.Tag.Get("required") == "1" || strings.ToLower(f.Tag.Get("required")) == "true",
-			TimeFormat:  f.Tag.Get("fmt"),
+			TimeFormats: splitTimeFormats(f.Tag.Get("fmt")),
+			NumFmt:      f.Tag.Get("numfmt"),
+			Transforms:  splitAndTrim(f.Tag.Get("transform")),
 			ColIndexTag: -1,
+			ColFrom:     -1,
+			ColTo:       -1,
+		}
+		fm.ValueType = f.Type
+		if isSliceField(fm.ValueType) {
+			fm.ValueType = fm.ValueType.Elem()
+		}
+		for fm.ValueType.Kind() == reflect.Ptr {
+			fm.ValueType = fm.ValueType.Elem()
+		}
+		if enumTag := f.Tag.Get("enum"); enumTag != "" {
+			if err := parseEnumTag(fm, enumTag, fm.ValueType); err != nil {
+				return err
+			}
+		}
+		fm.Unique = parseUniqueTag(f.Tag.Get("unique"))
+		fm.Lookup = strings.TrimSpace(f.Tag.Get("lookup"))
+		fm.Default, fm.HasDefault = f.Tag.Lookup("default")
+		if err := parseEmptyTag(fm, f.Tag.Get("empty")); err != nil {
+			return err
+		}
+
+		// Catch-all map: excel:",rest"
+		if excelTag == restTag {
+			if f.Type != stringMapType && !(f.Type.Kind() == reflect.Map && f.Type.ConvertibleTo(stringMapType)) {
+				return fmt.Errorf("excelio: field %s: %q needs a map[string]string field", name, restTag)
+			}
+			if m.Rest != nil {
+				return fmt.Errorf("excelio: field %s: only one %q field is allowed", name, restTag)
+			}
+			fm.ColumnNames = nil
+			fm.Rest = true
+			m.Rest = fm
+			m.FieldByName[path] = fm
+			continue
+		}
+		if prefix != "" {
+			for j, n := range fm.ColumnNames {
+				fm.ColumnNames[j] = prefix + n
+			}
 		}
 
 		// Header-based mapping.
-		for _, name := range fm.ColumnNames {
-			key := strings.ToLower(name)
-			m.HeaderToField[key] = fm
+		if !isSliceField(f.Type) {
+			for _, name := range fm.ColumnNames {
+				key := strings.ToLower(name)
+				m.HeaderToField[key] = fm
+			}
 		}
 
 		// Index-based mapping: col:"2"
@@ -284,15 +648,30 @@ func getTypeMeta(t reflect.Type) (*typeMeta, error) {
 			fm.ColLetterTag = strings.ToUpper(strings.TrimSpace(excelColTag))
 		}
 
+		// Pattern-based mapping: excelre:"^Price \\(.*\\)"
+		if excelReTag != "" {
+			re, err := regexp.Compile(excelReTag)
+			if err != nil {
+				return fmt.Errorf("excelio: field %s: invalid excelre tag: %w", name, err)
+			}
+			fm.HeaderRe = re
+		}
+
+		m.FieldByName[path] = fm
+		if isSliceField(f.Type) {
+			if err := parseMultiTags(fm, strings.TrimSpace(excelColTag)); err != nil {
+				return err
+			}
+			m.Multi = append(m.Multi, fm)
+			continue
+		}
 		m.Fields = append(m.Fields, fm)
-		m.FieldByName[f.Name] = fm
 	}
-
-	metaCache.Store(t, m)
-	return m, nil
+	return nil
 }
 
-// FindFieldByName returns the fieldMeta for a given struct field name.
+// FindFieldByName returns the fieldMeta for a given struct field path
+// (e.g. "Code" or "Billing.Street").
 func (m *typeMeta) FindFieldByName(name string) *fieldMeta {
 	if m.FieldByName == nil {
 		return nil
@@ -300,6 +679,30 @@ func (m *typeMeta) FindFieldByName(name string) *fieldMeta {
 	return m.FieldByName[name]
 }
 
+// findFieldByNamespace returns the fieldMeta for a validator namespace such as
+// "Product.Billing.Street" (the leading root type name is stripped).
+func (m *typeMeta) findFieldByNamespace(ns string) *fieldMeta {
+	if i := strings.IndexByte(ns, '.'); i >= 0 {
+		ns = ns[i+1:]
+	}
+	return m.FindFieldByName(ns)
+}
+
+// fieldForSet returns the field at the given index path of v, allocating nil
+// pointer-to-struct fields along the way so the leaf can be set.
+func fieldForSet(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
 /* =========================================================
  *  Column Helpers
  * ========================================================= */
@@ -341,31 +744,21 @@ func colIndexFromLetter(s string) int {
  *  Header Helpers
  * ========================================================= */
 
-// parseHeader reads the header row and returns a map[columnIndex]headerText.
-func parseHeader(f *excelize.File, sheet string, headerRow int) (map[int]string, error) {
-	rows, err := f.Rows(sheet)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	curRow := 0
-	for rows.Next() {
-		curRow++
-		cols, err := rows.Columns()
-		if err != nil {
-			return nil, err
-		}
-		if curRow == headerRow {
-			m := make(map[int]string, len(cols))
-			for i, c := range cols {
-				m[i] = strings.TrimSpace(c)
-			}
-			return m, nil
-		}
-	}
-
-	return nil, fmt.Errorf("excelio: header row %d not found", headerRow)
+// parseHeader converts the header row cells into a map[columnIndex]headerText
+// and a lookup of header keys (lowercased, normalized per Options.HeaderNorm)
+// to column index. When a header appears more than once, the left-most column wins.
+func parseHeader(cols []string, o *Options) (map[int]string, map[string]int) {
+	headerMap := make(map[int]string, len(cols))
+	headerIndex := make(map[string]int, len(cols))
+	for i, c := range cols {
+		h := strings.TrimSpace(c)
+		headerMap[i] = h
+		key := headerKey(h, o)
+		if _, dup := headerIndex[key]; key != "" && !dup {
+			headerIndex[key] = i
+		}
+	}
+	return headerMap, headerIndex
 }
 
 /* =========================================================
@@ -384,18 +777,42 @@ func parseBool(raw string) (bool, error) {
 	return false, fmt.Errorf("invalid bool: %q", raw)
 }
 
-// excelSerialToTime converts an Excel serial date (1900 date system) to time.Time (UTC).
-// Note: This uses the common "1899-12-30" base to match Excel's 1900 system behavior.
-func excelSerialToTime(serial float64) (time.Time, error) {
-	if serial <= 0 {
-		return time.Time{}, fmt.Errorf("invalid excel serial: %f", serial)
+// Largest serials Excel accepts: 9999-12-31 in the 1900 and 1904 date systems.
+const (
+	maxExcelSerial1900 = 2958465
+	maxExcelSerial1904 = 2957003
+)
+
+// excelSerialToTime converts an Excel serial date to time.Time (UTC).
+//
+// In the 1900 date system serial 1 is 1900-01-01 and, because Excel treats
+// 1900 as a leap year, serial 60 is the nonexistent 1900-02-29 (an error);
+// serials from 61 on are one day ahead of a plain day count. In the 1904
+// system serial 0 is 1904-01-01. Serials below 1 are times of day on day 0.
+func excelSerialToTime(serial float64, date1904 bool) (time.Time, error) {
+	maxSerial := float64(maxExcelSerial1900)
+	if date1904 {
+		maxSerial = maxExcelSerial1904
+	}
+	if math.IsNaN(serial) || serial < 0 || serial >= maxSerial+1 {
+		return time.Time{}, fmt.Errorf("excel serial %v out of range", serial)
 	}
 	const secondsInDay = 24 * 60 * 60
 
 	days := int64(serial)
 	frac := serial - float64(days)
 
-	base := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	var base time.Time
+	switch {
+	case date1904:
+		base = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+	case days == 60:
+		return time.Time{}, fmt.Errorf("excel serial 60 is 1900-02-29, which does not exist")
+	case days < 60:
+		base = time.Date(1899, 12, 31, 0, 0, 0, 0, time.UTC)
+	default:
+		base = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	}
 	t := base.AddDate(0, 0, int(days))
 
 	sec := int64(frac*secondsInDay + 0.5)
@@ -406,76 +823,137 @@ func excelSerialToTime(serial float64) (time.Time, error) {
 
 // parseTime attempts to parse a time value from the cell text.
 // It tries in this order:
-//  1. Custom format from fieldMeta.TimeFormat
+//  1. Layouts from the field's `fmt` tag ("02/01/2006|2006-01-02")
 //  2. RFC3339
-//  3. Several common date/time layouts
+//  3. TimeLayouts, or several common date/time layouts
 //  4. Excel serial number
-func parseTime(raw string, fm *fieldMeta) (time.Time, error) {
+//
+// With RawValues the stored value of a number or date cell is its serial
+// number, which is tried first; text cells start with the layouts. Times
+// without a zone are in TimeLocation (default UTC).
+func parseTime(raw string, fm *fieldMeta, o *Options, kind cellKind) (time.Time, error) {
 	s := strings.TrimSpace(raw)
 	if s == "" {
 		return time.Time{}, fmt.Errorf("empty time")
 	}
 
-	// 1. Custom format.
-	if fm != nil && fm.TimeFormat != "" {
-		if t, err := time.Parse(fm.TimeFormat, s); err == nil {
-			return t, nil
+	if storedNumber(o, kind) {
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			if t, err := excelSerialToTime(f, o.Date1904); err == nil {
+				return inLocation(t, o), nil
+			}
+		}
+	}
+
+	// 1. Custom formats.
+	if fm != nil && len(fm.TimeFormats) > 0 {
+		if t, ok, err := firstLayoutMatch(s, fm.TimeFormats, o); ok {
+			return t, err
 		}
 	}
 
 	// 2. RFC3339.
-	if t, err := time.Parse(time.RFC3339, s); err == nil {
+	if t, err := parseTimeLayout(s, time.RFC3339, o); err == nil {
 		return t, nil
 	}
 
-	// 3. Common formats.
-	layouts := []string{
-		"2006-01-02",
-		"02/01/2006",
-		"02-01-2006",
-		"2006/01/02",
-		"02/01/2006 15:04",
-		"2006-01-02 15:04",
-		"02-01-2006 15:04",
-	}
-	for _, layout := range layouts {
-		if t, err := time.Parse(layout, s); err == nil {
-			return t, nil
-		}
+	// 3. Configured or common formats.
+	if t, ok, err := firstLayoutMatch(s, timeLayoutsFor(o), o); ok {
+		return t, err
 	}
 
 	// 4. Excel serial number.
 	if f, err := strconv.ParseFloat(s, 64); err == nil {
-		if t, err2 := excelSerialToTime(f); err2 == nil {
-			return t, nil
+		if t, err2 := excelSerialToTime(f, o != nil && o.Date1904); err2 == nil {
+			return inLocation(t, o), nil
 		}
 	}
 
 	return time.Time{}, fmt.Errorf("cannot parse time: %q", raw)
 }
 
+// excelErrors are the error values Excel stores in cells.
+var excelErrors = map[string]bool{
+	"#NULL!": true, "#DIV/0!": true, "#VALUE!": true, "#REF!": true, "#NAME?": true,
+	"#NUM!": true, "#N/A": true, "#GETTING_DATA": true, "#SPILL!": true, "#CALC!": true,
+}
+
+// isExcelError reports whether s is an Excel error value such as "#N/A".
+func isExcelError(s string) bool {
+	return len(s) > 1 && s[0] == '#' && excelErrors[strings.ToUpper(s)]
+}
+
+// storedNumber reports whether a cell of the given kind holds a stored number
+// (RawValues): text cells and tag defaults are parsed as text.
+func storedNumber(o *Options, kind cellKind) bool {
+	return o != nil && o.RawCellValues && kind != cellText
+}
+
+// parseWholeFloat parses a stored number like "42" or "4.2E+1" that must be a
+// whole number; intErr is returned if s is not numeric at all.
+func parseWholeFloat(s string, intErr error) (int64, error) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, intErr
+	}
+	if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, fmt.Errorf("value %s is not a whole number", s)
+	}
+	return int64(f), nil
+}
+
 // setFieldValue sets a field value from a raw string, handling pointer and non-pointer types.
-func setFieldValue(field reflect.Value, fm *fieldMeta, raw string) error {
+// kind is the type of the source cell (cellText for tag defaults).
+func setFieldValue(field reflect.Value, fm *fieldMeta, raw string, o *Options, kind cellKind) error {
 	// Handle pointer types: if value is empty, keep nil; otherwise allocate and set.
 	if field.Kind() == reflect.Ptr {
-		trim := strings.TrimSpace(raw)
-		if trim == "" {
+		if isEmptyCell(raw, fm, o) {
 			return nil
 		}
 		elem := reflect.New(field.Type().Elem()).Elem()
-		if err := convertAndSet(elem, fm, raw); err != nil {
+		if err := convertAndSet(elem, fm, raw, o, kind); err != nil {
 			return err
 		}
 		field.Set(elem.Addr())
 		return nil
 	}
-	return convertAndSet(field, fm, raw)
+	return convertAndSet(field, fm, raw, o, kind)
 }
 
 // convertAndSet performs conversion for the underlying concrete kind.
-func convertAndSet(field reflect.Value, fm *fieldMeta, raw string) error {
+// Registered converters and types implementing encoding.TextUnmarshaler or
+// sql.Scanner are handled first (see decodeCustom).
+func convertAndSet(field reflect.Value, fm *fieldMeta, raw string, o *Options, kind cellKind) error {
+	if d := enumFor(field.Type(), fm, o); d != nil {
+		return d.set(field, raw)
+	}
+	if handled, err := decodeCustom(field, raw, o); handled {
+		return err
+	}
+	return convertKind(field, fm, raw, o, kind)
+}
+
+// convertKind converts raw by the kind of field alone, without enums and
+// converters (also used for the values of `enum` tags).
+func convertKind(field reflect.Value, fm *fieldMeta, raw string, o *Options, kind cellKind) error {
 	trim := strings.TrimSpace(raw)
 
+	if field.Kind() != reflect.String && isExcelError(trim) {
+		return fmt.Errorf("cell contains Excel error %s", trim)
+	}
+
+	// Formatted numbers ("1,234.50", "(120)", "12%", "฿1,000"); stored numbers
+	// read with RawValues are already plain.
+	if isNumberKind(field.Kind()) && localeNumbers(fm, o) {
+		if _, err := strconv.ParseFloat(trim, 64); err != nil || !storedNumber(o, kind) {
+			n, err := parseLocaleNumber(trim, numberLocale(o))
+			if err != nil {
+				return err
+			}
+			trim = n
+		}
+	}
+
 	switch field.Kind() {
 	case reflect.String:
 		field.SetString(raw)
@@ -483,6 +961,9 @@ func convertAndSet(field reflect.Value, fm *fieldMeta, raw string) error {
 
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		i, err := strconv.ParseInt(trim, 10, 64)
+		if err != nil && storedNumber(o, kind) {
+			i, err = parseWholeFloat(trim, err)
+		}
 		if err != nil {
 			return err
 		}
@@ -491,6 +972,13 @@ func convertAndSet(field reflect.Value, fm *fieldMeta, raw string) error {
 
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		u, err := strconv.ParseUint(trim, 10, 64)
+		if err != nil && storedNumber(o, kind) {
+			var i int64
+			if i, err = parseWholeFloat(trim, err); err == nil && i < 0 {
+				err = fmt.Errorf("negative value %s for unsigned field", trim)
+			}
+			u = uint64(i)
+		}
 		if err != nil {
 			return err
 		}
@@ -514,14 +1002,21 @@ func convertAndSet(field reflect.Value, fm *fieldMeta, raw string) error {
 		return nil
 
 	case reflect.Struct:
-		if field.Type() == reflect.TypeOf(time.Time{}) {
-			tm, err := parseTime(raw, fm)
+		if field.Type() == timeType {
+			tm, err := parseTime(raw, fm, o, kind)
 			if err != nil {
 				return err
 			}
 			field.Set(reflect.ValueOf(tm))
 			return nil
 		}
+		if isSQLNull(field.Type()) {
+			if err := convertAndSet(field.Field(0), fm, raw, o, kind); err != nil {
+				return err
+			}
+			field.Field(1).SetBool(true)
+			return nil
+		}
 	}
 
 	// If you want to be more permissive, you could skip unsupported kinds instead.
@@ -533,13 +1028,19 @@ func convertAndSet(field reflect.Value, fm *fieldMeta, raw string) error {
  * ========================================================= */
 
 // buildFieldColIndex resolves the final column index for each fieldMeta,
-// combining header-based, index-based (`col`) and letter-based (`excelcol`) mapping.
-func buildFieldColIndex(meta *typeMeta, headerIndex map[string]int) map[*fieldMeta]int {
+// combining index-based (`col`), letter-based (`excelcol`) and header-based
+// mapping: exact (or normalized) header text first, then MatchHeader
+// predicates and `excelre` patterns, then fuzzy matching if enabled.
+// It also returns how each bound field was matched, in struct order.
+// A predicate or pattern that matches more than one free column is an error.
+func buildFieldColIndex(meta *typeMeta, headerMap map[int]string, headerIndex map[string]int, o *Options) (map[*fieldMeta]int, []HeaderMatch, error) {
 	fieldColIndex := make(map[*fieldMeta]int, len(meta.Fields))
+	matches := make(map[*fieldMeta]HeaderMatch, len(meta.Fields))
 	for _, fm := range meta.Fields {
 		// 1. Explicit index: col:"2".
 		if fm.ColIndexTag >= 0 {
 			fieldColIndex[fm] = fm.ColIndexTag
+			matches[fm] = HeaderMatch{By: MatchByCol, Score: 1}
 			continue
 		}
 
@@ -547,6 +1048,7 @@ func buildFieldColIndex(meta *typeMeta, headerIndex map[string]int) map[*fieldMe
 		if fm.ColLetterTag != "" {
 			if idx := colIndexFromLetter(fm.ColLetterTag); idx >= 0 {
 				fieldColIndex[fm] = idx
+				matches[fm] = HeaderMatch{By: MatchByLetter, Score: 1}
 				continue
 			}
 		}
@@ -554,14 +1056,44 @@ func buildFieldColIndex(meta *typeMeta, headerIndex map[string]int) map[*fieldMe
 		// 3. Header-based: excel:"Code,Name"
 		if len(fm.ColumnNames) > 0 && len(headerIndex) > 0 {
 			for _, name := range fm.ColumnNames {
-				if idx, ok := headerIndex[strings.ToLower(strings.TrimSpace(name))]; ok {
+				if idx, ok := headerIndex[headerKey(name, o)]; ok {
 					fieldColIndex[fm] = idx
+					by := MatchByHeader
+					if !strings.EqualFold(headerMap[idx], strings.TrimSpace(name)) {
+						by = MatchByNormalized
+					}
+					matches[fm] = HeaderMatch{Header: headerMap[idx], By: by, Score: 1}
 					break
 				}
 			}
 		}
 	}
-	return fieldColIndex
+
+	// 4. Predicates (MatchHeader) and patterns (excelre) for the remaining fields.
+	if len(headerMap) > 0 {
+		if err := patternBind(meta, headerMap, fieldColIndex, matches, o); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// 5. Fuzzy fallback for the remaining header fields.
+	if o != nil && o.FuzzyThreshold > 0 && len(headerMap) > 0 {
+		fuzzyBind(meta, headerMap, fieldColIndex, matches, o)
+	}
+
+	ordered := make([]HeaderMatch, 0, len(matches))
+	for _, fm := range meta.Fields {
+		m, ok := matches[fm]
+		if !ok {
+			continue
+		}
+		idx := fieldColIndex[fm]
+		m.Field = fm.FieldName
+		m.ColIndex = idx + 1
+		m.ColLetter = colLetter(idx)
+		ordered = append(ordered, m)
+	}
+	return fieldColIndex, ordered, nil
 }
 
 // buildRowError creates a RowError populated with row/column information.
@@ -603,10 +1135,12 @@ func mapRow[T any](
 	t reflect.Type,
 	meta *typeMeta,
 	fieldColIndex map[*fieldMeta]int,
+	multi *multiColumns,
 	headerMap map[int]string,
 	o *Options,
 	rowIdx, logicalIdx int,
 	cols []string,
+	kinds []cellKind,
 ) (T, []RowError, bool) {
 	var zero T
 	v := reflect.New(t).Elem()
@@ -617,9 +1151,15 @@ func mapRow[T any](
 	for _, fm := range meta.Fields {
 		colIdx, ok := fieldColIndex[fm]
 		if !ok {
+			// Column not in the sheet: defaults still apply.
+			if err := fillEmpty(v, fm, emptyRule(fm, o), false, o); err != nil {
+				rowHasError = true
+				rowErrs = append(rowErrs, buildRowError(
+					rowIdx, logicalIdx, fm, -1, headerMap, cols, err,
+				))
+			}
 			continue
 		}
-		fm.lastColIndex = colIdx
 
 		// Column out of range.
 		if colIdx < 0 || colIdx >= len(cols) {
@@ -629,31 +1169,40 @@ func mapRow[T any](
 					rowIdx, logicalIdx, fm, colIdx, headerMap, cols,
 					fmt.Errorf("required column out of range"),
 				))
+			} else if err := fillEmpty(v, fm, emptyRule(fm, o), true, o); err != nil {
+				rowHasError = true
+				rowErrs = append(rowErrs, buildRowError(
+					rowIdx, logicalIdx, fm, colIdx, headerMap, cols, err,
+				))
 			}
 			continue
 		}
 
-		raw := cols[colIdx]
-		trim := strings.TrimSpace(raw)
+		raw := readTransform(cols[colIdx], fm, o)
 
 		// Empty value.
-		if trim == "" {
+		if isEmptyCell(raw, fm, o) {
 			if fm.Required {
 				rowHasError = true
 				rowErrs = append(rowErrs, buildRowError(
 					rowIdx, logicalIdx, fm, colIdx, headerMap, cols,
 					fmt.Errorf("required value is empty"),
 				))
+			} else if err := fillEmpty(v, fm, emptyRule(fm, o), true, o); err != nil {
+				rowHasError = true
+				rowErrs = append(rowErrs, buildRowError(
+					rowIdx, logicalIdx, fm, colIdx, headerMap, cols, err,
+				))
 			}
 			continue
 		}
 
-		field := v.FieldByIndex(fm.Index)
+		field := fieldForSet(v, fm.Index)
 		if !field.CanSet() {
 			continue
 		}
 
-		if err := setFieldValue(field, fm, raw); err != nil {
+		if err := setFieldValue(field, fm, raw, o, kindAt(kinds, colIdx)); err != nil {
 			rowHasError = true
 			rowErrs = append(rowErrs, buildRowError(
 				rowIdx, logicalIdx, fm, colIdx, headerMap, cols, err,
@@ -661,6 +1210,14 @@ func mapRow[T any](
 		}
 	}
 
+	// Slice fields and the rest map.
+	if multi != nil {
+		if errs := mapMulti(v, meta, multi, headerMap, o, rowIdx, logicalIdx, cols, kinds); len(errs) > 0 {
+			rowHasError = true
+			rowErrs = append(rowErrs, errs...)
+		}
+	}
+
 	obj := v.Interface().(T)
 
 	// Struct-level validation using go-playground/validator (if configured).
@@ -669,10 +1226,17 @@ func mapRow[T any](
 			if verrs, ok := e.(validator.ValidationErrors); ok {
 				for _, fe := range verrs {
 					rowHasError = true
-					fm := meta.FindFieldByName(fe.StructField())
+					fm := meta.findFieldByNamespace(fe.StructNamespace())
 					colIdx := -1
 					if fm != nil {
-						colIdx = fm.lastColIndex
+						if idx, ok := fieldColIndex[fm]; ok {
+							colIdx = idx
+						}
+					} else if path, elem, ok := splitElementPath(fe.StructNamespace()); ok {
+						// Element of a slice field, e.g. "Product.Sizes[2]".
+						if fm = meta.findFieldByNamespace(path); fm != nil {
+							colIdx = multi.sliceColumn(fm, elem)
+						}
 					}
 
 					displayName := fe.Field()
@@ -728,7 +1292,7 @@ func resolveSheet(f *excelize.File, o *Options) (string, error) {
 }
 
 /* =========================================================
- *  Core: read/stream from *excelize.File
+ *  Core: read/stream from a book (XLSX or CSV/TSV)
  * ========================================================= */
 
 // isRowEmpty checks whether all cells in a row are empty (after trimming).
@@ -741,61 +1305,145 @@ func isRowEmpty(cols []string) bool {
 	return true
 }
 
-// readFromExcelFile implements the core "read everything into slice" logic.
-func readFromExcelFile[T any](f *excelize.File, o *Options) ([]T, []RowError, error) {
-	sheet, err := resolveSheet(f, o)
-	if err != nil {
-		return nil, nil, err
-	}
+// rowFunc receives every data row produced by scanSheet.
+// ok reports whether obj is valid. Rows that could not be read at all are
+// reported with logicalIdx == -1, Aggregate errors after the last row with
+// rowIdx == 0.
+type rowFunc[T any] func(rowIdx, logicalIdx int, obj T, rowErrs []RowError, ok bool) error
+
+// rawRow is a data row (or unreadable row) as produced by sheetScanner.produce,
+// before mapping.
+type rawRow struct {
+	seq        int // 0-based sequence number, used to restore order
+	rowIdx     int
+	logicalIdx int
+	cols       []string
+	kinds      []cellKind // RawValues cell types; nil if unknown
+	readErr    error
+}
 
-	var headerMap map[int]string
-	headerIndex := make(map[string]int)
+// mappedRow is a rawRow after mapping into T.
+type mappedRow[T any] struct {
+	seq        int
+	rowIdx     int
+	logicalIdx int
+	obj        T
+	rowErrs    []RowError
+	ok         bool
+}
 
-	// Build header index if header row is configured.
-	if o.HeaderRow > 0 {
-		headerMap, err = parseHeader(f, sheet, o.HeaderRow)
-		if err != nil {
-			return nil, nil, err
-		}
-		for idx, name := range headerMap {
-			n := strings.ToLower(strings.TrimSpace(name))
-			if n != "" {
-				headerIndex[n] = idx
-			}
-		}
-	}
+// sheetScanner holds the per-read state of scanSheet. Header-derived fields
+// are set by produce before the first data row is emitted and are read-only
+// afterwards, so mapOne may run on several goroutines.
+type sheetScanner[T any] struct {
+	t     reflect.Type
+	meta  *typeMeta
+	o     *Options
+	sheet string
+
+	headerMap     map[int]string
+	fieldColIndex map[*fieldMeta]int
+	multi         *multiColumns // slice fields & rest map; nil if T has none
+	matches       []HeaderMatch
+
+	headerRows [][]string   // collected rows of a multi-row header
+	merges     []mergeRange // merged cells, loaded for multi-row headers
+
+	rules *rowRules // cross-row rules; nil if T and the options have none
+}
 
+// newSheetScanner resolves the metadata of T and opens the rows of the sheet
+// selected by o. The caller must close the returned iterator.
+func newSheetScanner[T any](b book, o *Options) (*sheetScanner[T], rowIterator, error) {
 	t := reflect.TypeOf((*T)(nil)).Elem()
 	meta, err := getTypeMeta(t)
 	if err != nil {
 		return nil, nil, err
 	}
+	if err := checkTransforms(meta, o); err != nil {
+		return nil, nil, err
+	}
+	rules, err := newRowRules(meta, o)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	fieldColIndex := buildFieldColIndex(meta, headerIndex)
-
-	rows, err := f.Rows(sheet)
+	rows, err := b.rows(o)
 	if err != nil {
 		return nil, nil, err
 	}
-	defer rows.Close()
 
-	var result []T
-	var errs []RowError
+	sc := &sheetScanner[T]{
+		t:     t,
+		meta:  meta,
+		o:     o,
+		sheet: o.sheetResolved,
+		rules: rules,
+	}
+	sc.fieldColIndex, _, _ = buildFieldColIndex(meta, nil, nil, o)
+	sc.multi, _ = bindMultiColumns(meta, nil, nil, sc.fieldColIndex, o)
+
+	if mb, ok := b.(mergeBook); ok && o.headerEnd() > o.HeaderRow {
+		if sc.merges, err = mb.merges(o); err != nil {
+			rows.Close()
+			return nil, nil, err
+		}
+	}
+	return sc, rows, nil
+}
+
+// produce walks the sheet once: the header row is detected first if
+// AutoHeader is set, the header row(s) (if configured) are parsed when
+// reached, and every non-empty data row or unreadable row is passed to emit
+// in order. An error returned by emit stops the walk.
+func (sc *sheetScanner[T]) produce(rows rowIterator, emit func(rawRow) error) error {
+	o := sc.o
+	if o.AutoHeaderRows > 0 {
+		var err error
+		if rows, err = sc.detectHeader(rows); err != nil {
+			return err
+		}
+	}
+	headerFound := o.HeaderRow <= 0
+	headerEnd := o.headerEnd()
+
 	rowIdx := 0
 	dataIdx := 0
+	seq := 0
 
 	for rows.Next() {
+		if err := ctxErr(o.ctx); err != nil {
+			return err
+		}
 		rowIdx++
 		cols, err := rows.Columns()
+		if o.HeaderRow > 0 && rowIdx >= o.HeaderRow && rowIdx <= headerEnd {
+			if err != nil {
+				return err
+			}
+			if headerEnd > o.HeaderRow {
+				sc.headerRows = append(sc.headerRows, cols)
+				if rowIdx < headerEnd {
+					continue
+				}
+				cols = composeHeader(sc.headerRows, o.HeaderRow, sc.merges)
+			}
+			if err := sc.bindHeader(cols); err != nil {
+				return err
+			}
+			headerFound = true
+			continue
+		}
 		if err != nil {
-			errs = append(errs, RowError{
-				ExcelRowIndex: rowIdx,
-				Err:           fmt.Errorf("read row: %w", err),
-			})
+			// Row read error: still pass it on for logging/use.
+			if eErr := emit(rawRow{seq: seq, rowIdx: rowIdx, logicalIdx: -1, readErr: err}); eErr != nil {
+				return eErr
+			}
+			seq++
 			continue
 		}
 
-		if rowIdx < o.FirstDataRow {
+		if rowIdx < o.FirstDataRow || !headerFound {
 			continue
 		}
 		if isRowEmpty(cols) {
@@ -808,97 +1456,148 @@ func readFromExcelFile[T any](f *excelize.File, o *Options) ([]T, []RowError, er
 			logicalIdx = o.RowIndexMapper(rowIdx, dataIdx)
 		}
 
-		obj, rowErrs, ok := mapRow[T](t, meta, fieldColIndex, headerMap, o, rowIdx, logicalIdx, cols)
-		if len(rowErrs) > 0 {
-			errs = append(errs, rowErrs...)
-		}
-		if ok {
-			result = append(result, obj)
+		if eErr := emit(rawRow{seq: seq, rowIdx: rowIdx, logicalIdx: logicalIdx, cols: cols, kinds: rowKinds(rows)}); eErr != nil {
+			return eErr
 		}
+		seq++
 	}
 
-	return result, errs, nil
-}
-
-// streamFromExcelFile implements the core streaming logic using Options.streamHandler.
-func streamFromExcelFile[T any](f *excelize.File, o *Options) ([]RowError, error) {
-	if o.streamHandler == nil {
-		return nil, fmt.Errorf("excelio: OnStreamRow() is required for Stream/StreamFile")
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("excelio: read row %d: %w", rowIdx+1, err)
 	}
+	if !headerFound {
+		return fmt.Errorf("excelio: header row %d not found", o.HeaderRow)
+	}
+	return nil
+}
 
-	sheet, err := resolveSheet(f, o)
+// bindHeader parses the header row and resolves the column of every field.
+// With StrictHeaders, a mismatch is returned as a *HeaderError.
+func (sc *sheetScanner[T]) bindHeader(cols []string) error {
+	var headerIndex map[string]int
+	sc.headerMap, headerIndex = parseHeader(cols, sc.o)
+	var err error
+	sc.fieldColIndex, sc.matches, err = buildFieldColIndex(sc.meta, sc.headerMap, headerIndex, sc.o)
 	if err != nil {
-		return nil, err
+		return err
 	}
-
-	var headerMap map[int]string
-	headerIndex := make(map[string]int)
-
-	// Build header index if header row is configured.
-	if o.HeaderRow > 0 {
-		headerMap, err = parseHeader(f, sheet, o.HeaderRow)
-		if err != nil {
-			return nil, err
-		}
-		for idx, name := range headerMap {
-			n := strings.ToLower(strings.TrimSpace(name))
-			if n != "" {
-				headerIndex[n] = idx
-			}
-		}
+	var multiMatches []HeaderMatch
+	sc.multi, multiMatches = bindMultiColumns(sc.meta, sc.headerMap, headerIndex, sc.fieldColIndex, sc.o)
+	sc.matches = append(sc.matches, multiMatches...)
+	if sc.o.onHeader != nil {
+		sc.o.onHeader(sc.report())
 	}
-
-	t := reflect.TypeOf((*T)(nil)).Elem()
-	meta, err := getTypeMeta(t)
-	if err != nil {
-		return nil, err
+	if sc.o.StrictHeaders {
+		return sc.report().Err()
 	}
+	return nil
+}
+
+// report describes how the header row was bound.
+func (sc *sheetScanner[T]) report() HeaderReport {
+	return buildHeaderReport(sc.meta, sc.headerMap, sc.fieldColIndex, sc.multi, sc.matches, sc.o)
+}
 
-	fieldColIndex := buildFieldColIndex(meta, headerIndex)
+// mapOne maps a rawRow into T and tags its errors with the sheet name.
+func (sc *sheetScanner[T]) mapOne(rr rawRow) mappedRow[T] {
+	m := mappedRow[T]{seq: rr.seq, rowIdx: rr.rowIdx, logicalIdx: rr.logicalIdx}
+	if rr.readErr != nil {
+		m.rowErrs = []RowError{{
+			Sheet:         sc.sheet,
+			ExcelRowIndex: rr.rowIdx,
+			Err:           fmt.Errorf("read row: %w", rr.readErr),
+		}}
+		return m
+	}
+	m.obj, m.rowErrs, m.ok = mapRow[T](sc.t, sc.meta, sc.fieldColIndex, sc.multi, sc.headerMap, sc.o, rr.rowIdx, rr.logicalIdx, rr.cols, rr.kinds)
+	for i := range m.rowErrs {
+		m.rowErrs[i].Sheet = sc.sheet
+	}
+	return m
+}
 
-	rows, err := f.Rows(sheet)
+// scanSheet implements the core read loop shared by Read and Stream.
+// Rows are produced in sheet order, mapped into T (on Options.Workers
+// goroutines if > 1) and passed to fn on the calling goroutine.
+// An error returned by fn stops the scan and is returned as-is.
+func scanSheet[T any](b book, o *Options, fn rowFunc[T]) error {
+	sc, rows, err := newSheetScanner[T](b, o)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer rows.Close()
 
-	rowIdx := 0
-	dataIdx := 0
-
-	var allErrs []RowError
-	var fatalErr error
+	// Progress counts the errors of the cross-row rules too.
+	fn, deliver, finish := trackProgress(o, fn)
+	if sc.rules != nil {
+		fn = checkRows(sc, fn)
+	}
 
-	for rows.Next() {
-		rowIdx++
-		cols, err := rows.Columns()
-		if err != nil {
-			// Row read error: still pass to handler for logging/use.
-			re := RowError{
-				ExcelRowIndex: rowIdx,
-				Err:           fmt.Errorf("read row: %w", err),
+	if o.Workers > 1 {
+		err = scanParallel(sc, rows, fn)
+	} else {
+		err = sc.produce(rows, func(rr rawRow) error {
+			m := sc.mapOne(rr)
+			return fn(m.rowIdx, m.logicalIdx, m.obj, m.rowErrs, m.ok)
+		})
+	}
+	if err != nil {
+		return err
+	}
+	if sc.rules != nil {
+		var zero T
+		for _, e := range sc.rules.firstDups {
+			if err := deliver(e.ExcelRowIndex, e.LogicalIndex, zero, []RowError{e}, false); err != nil {
+				return err
 			}
-			allErrs = append(allErrs, re)
-			if hErr := o.streamHandler(rowIdx, -1, nil, []RowError{re}); hErr != nil {
-				fatalErr = hErr
-				break
+		}
+		if errs := sc.rules.finish(sc.ruleScope()); len(errs) > 0 {
+			if err := deliver(0, 0, zero, errs, false); err != nil {
+				return err
 			}
-			continue
 		}
+	}
+	finish()
+	return nil
+}
 
-		if rowIdx < o.FirstDataRow {
-			continue
+// readFromBook implements the core "read everything into slice" logic.
+func readFromBook[T any](b book, o *Options) ([]T, []RowError, error) {
+	var result []T
+	var errs []RowError
+	lastRow, sorted := 0, true
+
+	o.firstDuplicates = true
+	err := scanSheet(b, o, func(rowIdx, logicalIdx int, obj T, rowErrs []RowError, ok bool) error {
+		if len(rowErrs) > 0 {
+			errs = append(errs, rowErrs...)
 		}
-		if isRowEmpty(cols) {
-			continue
+		if ok {
+			result = append(result, obj)
 		}
-
-		dataIdx++
-		logicalIdx := dataIdx
-		if o.RowIndexMapper != nil {
-			logicalIdx = o.RowIndexMapper(rowIdx, dataIdx)
+		if rowIdx > 0 && rowIdx < lastRow {
+			sorted = false // first-row duplicate errors arrive after the last row
 		}
+		lastRow = max(lastRow, rowIdx)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if !sorted && !o.Unordered {
+		sortByRow(errs)
+	}
+	return result, errs, nil
+}
+
+// streamFromBook implements the core streaming logic using Options.streamHandler.
+func streamFromBook[T any](b book, o *Options) ([]RowError, error) {
+	if o.streamHandler == nil {
+		return nil, fmt.Errorf("excelio: OnStreamRow() or OnStreamBatch() is required for Stream/StreamFile")
+	}
 
-		obj, rowErrs, ok := mapRow[T](t, meta, fieldColIndex, headerMap, o, rowIdx, logicalIdx, cols)
+	var allErrs []RowError
+	err := scanSheet(b, o, func(rowIdx, logicalIdx int, obj T, rowErrs []RowError, ok bool) error {
 		if len(rowErrs) > 0 {
 			allErrs = append(allErrs, rowErrs...)
 		}
@@ -908,129 +1607,60 @@ func streamFromExcelFile[T any](f *excelize.File, o *Options) ([]RowError, error
 			objCopy := obj // ensure address is stable
 			objAny = &objCopy
 		}
-
-		if hErr := o.streamHandler(rowIdx, logicalIdx, objAny, rowErrs); hErr != nil {
-			fatalErr = hErr
-			break
-		}
-	}
-
-	if fatalErr != nil {
-		return allErrs, fatalErr
+		return o.streamHandler(rowIdx, logicalIdx, objAny, rowErrs)
+	})
+	if err == nil && o.streamFlush != nil {
+		err = o.streamFlush()
 	}
-	return allErrs, nil
+	return allErrs, err
 }
 
 /* =========================================================
  *  Public API: Read / Stream
  * ========================================================= */
 
-// ReadFile reads an Excel file from a file path and returns:
+// ReadFile reads an Excel (or CSV/TSV) file from a file path and returns:
 //   - a slice of successfully mapped objects
 //   - a slice of RowError for all rows with issues
+//
+// The format is detected from the extension (or content); use Format(...) to force it.
 func ReadFile[T any](path string, opts ...Option) ([]T, []RowError, error) {
-	var o Options
-	for _, opt := range opts {
-		opt(&o)
-	}
-	applyDefaults(&o)
-
-	f, err := excelize.OpenFile(path)
-	if err != nil {
-		return nil, nil, err
-	}
-	defer f.Close()
-
-	return readFromExcelFile[T](f, &o)
+	return ReadFileContext[T](context.Background(), path, opts...)
 }
 
-// Read reads an Excel file from an io.Reader (e.g. HTTP upload, memory buffer)
-// and returns:
+// Read reads an Excel (or CSV/TSV) file from an io.Reader (e.g. HTTP upload,
+// memory buffer) and returns:
 //   - a slice of successfully mapped objects
 //   - a slice of RowError for all rows with issues
+//
+// The format is detected from the leading bytes; use Format(...) to force it.
 func Read[T any](r io.Reader, opts ...Option) ([]T, []RowError, error) {
-	var o Options
-	for _, opt := range opts {
-		opt(&o)
-	}
-	applyDefaults(&o)
-
-	f, err := excelize.OpenReader(r)
-	if err != nil {
-		return nil, nil, err
-	}
-	defer f.Close()
-
-	return readFromExcelFile[T](f, &o)
+	return ReadContext[T](context.Background(), r, opts...)
 }
 
-// StreamFile streams an Excel file from a file path, calling the handler
+// StreamFile streams an Excel (or CSV/TSV) file from a file path, calling the handler
 // supplied via OnStreamRow(...) for each non-empty data row.
 // It returns a slice of RowError for all rows with issues.
-// If ErrCol(...) is set and there are errors, it will also write error messages
-// back into the original file in the specified column.
+// If ErrCol(...) or SheetErrCol(...) is set and there are errors, it will also
+// write error messages back into the original file in the specified column.
 func StreamFile[T any](path string, opts ...Option) ([]RowError, error) {
-	var o Options
-	for _, opt := range opts {
-		opt(&o)
-	}
-	applyDefaults(&o)
-
-	if o.streamHandler == nil {
-		return nil, fmt.Errorf("excelio: OnStreamRow() is required for StreamFile")
-	}
-
-	f, err := excelize.OpenFile(path)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
-
-	allErrs, err := streamFromExcelFile[T](f, &o)
-	if err != nil {
-		return allErrs, err
-	}
-
-	// Auto-write errors back to the file if configured.
-	if o.ErrorColumnIndex > 0 && len(allErrs) > 0 {
-		if err := WriteErrors(path, allErrs, opts...); err != nil {
-			return allErrs, err
-		}
-	}
-
-	return allErrs, nil
+	return StreamFileContext[T](context.Background(), path, opts...)
 }
 
-// Stream streams an Excel file from an io.Reader, calling the handler supplied
+// Stream streams an Excel (or CSV/TSV) file from an io.Reader, calling the handler supplied
 // via OnStreamRow(...) for each non-empty data row.
 // It returns a slice of RowError for all rows with issues.
 // This variant does not modify the original source (no path), but you can
 // later call WriteErrorsTo(...) if you want to produce a new file with errors.
 func Stream[T any](r io.Reader, opts ...Option) ([]RowError, error) {
-	var o Options
-	for _, opt := range opts {
-		opt(&o)
-	}
-	applyDefaults(&o)
-
-	if o.streamHandler == nil {
-		return nil, fmt.Errorf("excelio: OnStreamRow() is required for Stream")
-	}
-
-	f, err := excelize.OpenReader(r)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
-
-	return streamFromExcelFile[T](f, &o)
+	return StreamContext[T](context.Background(), r, opts...)
 }
 
 /* =========================================================
  *  WriteErrors: path & io.Writer versions
  * ========================================================= */
 
-// WriteErrors writes error messages into an existing Excel file identified by path.
+// WriteErrors writes error messages into an existing Excel (or CSV/TSV) file identified by path.
 // It uses ErrCol(...) to determine which column to write to.
 func WriteErrors(path string, errs []RowError, opts ...Option) error {
 	if len(errs) == 0 {
@@ -1041,17 +1671,17 @@ func WriteErrors(path string, errs []RowError, opts ...Option) error {
 	for _, opt := range opts {
 		opt(&o)
 	}
-	if o.ErrorColumnIndex <= 0 {
+	if o.ErrorColumnIndex <= 0 && len(o.SheetErrorColumns) == 0 {
 		return fmt.Errorf("excelio: ErrCol() / ErrorColumnIndex must be > 0 for WriteErrors")
 	}
 
-	f, err := excelize.OpenFile(path)
+	b, err := openBookFile(path, &o)
 	if err != nil {
 		return err
 	}
-	defer f.Close()
+	defer b.Close()
 
-	return writeErrorsToExcelFile(f, errs, &o, nil)
+	return writeErrorsToBook(b, errs, &o, nil, path)
 }
 
 // WriteErrorsTo writes error messages into a copy of the Excel file read from r,
@@ -1069,37 +1699,60 @@ func WriteErrorsTo(w io.Writer, r io.Reader, errs []RowError, opts ...Option) er
 	for _, opt := range opts {
 		opt(&o)
 	}
-	if o.ErrorColumnIndex <= 0 {
+	if o.ErrorColumnIndex <= 0 && len(o.SheetErrorColumns) == 0 {
 		return fmt.Errorf("excelio: ErrCol() / ErrorColumnIndex must be > 0 for WriteErrorsTo")
 	}
 
-	f, err := excelize.OpenReader(r)
+	b, err := openBookReader(r, &o)
 	if err != nil {
 		return err
 	}
-	defer f.Close()
+	defer b.Close()
 
-	return writeErrorsToExcelFile(f, errs, &o, w)
+	return writeErrorsToBook(b, errs, &o, w, "")
+}
+
+// writeErrorsToBook dispatches error write-back to the XLSX or CSV/TSV implementation.
+func writeErrorsToBook(b book, errs []RowError, o *Options, w io.Writer, path string) error {
+	switch b := b.(type) {
+	case *xlsxBook:
+		return writeErrorsToExcelFile(b.f, errs, o, w)
+	case *csvBook:
+		return writeErrorsToCSV(b, errs, o, w, path)
+	}
+	return errors.New("excelio: unsupported source for error write-back")
+}
+
+// errorColumnFor returns the 1-based error column for a sheet.
+func errorColumnFor(sheet string, o *Options) int {
+	if idx, ok := o.SheetErrorColumns[sheet]; ok {
+		return idx
+	}
+	return o.ErrorColumnIndex
 }
 
 // writeErrorsToExcelFile writes the provided RowError list into the given
-// *excelize.File f, using the sheet from Options and error column from Options.
+// *excelize.File f. Each error goes to its own RowError.Sheet (or, if empty, the
+// sheet from Options) and that sheet's error column (SheetErrCol / ErrCol).
 // If w == nil, it saves the file to disk (f.Save()).
 // If w != nil, it writes the Excel content to w (f.Write(w)).
 func writeErrorsToExcelFile(f *excelize.File, errs []RowError, o *Options, w io.Writer) error {
-	sheet, err := resolveSheet(f, o)
-	if err != nil {
-		return err
-	}
-
-	errColIdx := o.ErrorColumnIndex - 1
-	errColLetter := colLetter(errColIdx)
-
 	for _, re := range errs {
 		if re.ExcelRowIndex <= 0 {
 			continue
 		}
-		cell := fmt.Sprintf("%s%d", errColLetter, re.ExcelRowIndex)
+		sheet := re.Sheet
+		if sheet == "" {
+			var err error
+			if sheet, err = resolveSheet(f, o); err != nil {
+				return err
+			}
+		}
+		errCol := errorColumnFor(sheet, o)
+		if errCol <= 0 {
+			return fmt.Errorf("excelio: no error column configured for sheet %q", sheet)
+		}
+		cell := fmt.Sprintf("%s%d", colLetter(errCol-1), re.ExcelRowIndex)
 		oldVal, _ := f.GetCellValue(sheet, cell)
 		msg := re.Err.Error()
 		if oldVal != "" {
@@ -1123,6 +1776,8 @@ func writeErrorsToExcelFile(f *excelize.File, errs []RowError, o *Options, w io.
 // StreamWriter writes rows in streaming mode based on struct tags
 // (`excel`, `col`, `excelcol`) and reuses the same metadata/cache
 // as the read side.
+// Slice and `,rest` map fields are expanded into columns sized by the first
+// row written, so with such fields the header row is written with that row.
 //
 // Usage:
 //
@@ -1137,8 +1792,7 @@ func writeErrorsToExcelFile(f *excelize.File, errs []RowError, o *Options, w io.
 //	    _ = sw.WriteRow(&p)
 //	}
 type StreamWriter[T any] struct {
-	f      *excelize.File
-	sw     *excelize.StreamWriter
+	sink   rowSink
 	opts   *Options
 	meta   *typeMeta
 	fields []*fieldMeta
@@ -1146,14 +1800,20 @@ type StreamWriter[T any] struct {
 	fieldColIndex map[*fieldMeta]int
 	maxColIndex   int
 
+	// Slice fields and the rest map are laid out from the first row
+	// (see multicol.go); until then the header row is pending.
+	multi         []multiLayout
+	layoutPending bool
+
 	curRow int // next row to write (Excel 1-based)
 
-	out    io.Writer
-	path   string
 	closed bool
 
 	// rowBuf is a reusable buffer for row values to avoid per-row allocations.
 	rowBuf []interface{}
+
+	// numFmtStyles caches the style ID of each `numfmt` code (XLSX only).
+	numFmtStyles map[string]int
 }
 
 // buildFieldOrderForWrite determines a stable column index for each mapped field.
@@ -1169,7 +1829,7 @@ type StreamWriter[T any] struct {
 func buildFieldOrderForWrite(meta *typeMeta) (fields []*fieldMeta, index map[*fieldMeta]int, maxCol int) {
 	index = make(map[*fieldMeta]int, len(meta.Fields))
 
-	used := map[int]bool{}
+	used := reservedWriteColumns(meta) // excelcol ranges of slice fields
 	autoIndex := 0
 	maxCol = -1
 
@@ -1234,54 +1894,88 @@ func fieldHeaderName(fm *fieldMeta) string {
 }
 
 // valueToCell converts a field value to a cell value that excelize can handle.
-// It respects time formats from fieldMeta.TimeFormat if set.
-func valueToCell(v reflect.Value, fm *fieldMeta) any {
+// Times are formatted by formatTime (first `fmt` layout, TimeLocation, BuddhistEra).
+// Registered converters and types implementing encoding.TextMarshaler or
+// driver.Valuer are handled first (see encodeCustom).
+func valueToCell(v reflect.Value, fm *fieldMeta, o *Options) (any, error) {
 	if !v.IsValid() {
-		return ""
+		return "", nil
 	}
 
 	// Handle pointer.
 	if v.Kind() == reflect.Ptr {
 		if v.IsNil() {
-			return ""
+			return "", nil
 		}
 		v = v.Elem()
 	}
 
+	if d := enumFor(v.Type(), fm, o); d != nil {
+		return d.label(v)
+	}
+	if out, handled, err := encodeCustom(v, fm, o); handled {
+		return out, err
+	}
+
 	switch v.Kind() {
 	case reflect.String:
-		return v.String()
+		return v.String(), nil
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return v.Int()
+		return v.Int(), nil
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return v.Uint()
+		return v.Uint(), nil
 	case reflect.Float32, reflect.Float64:
-		return v.Float()
+		return v.Float(), nil
 	case reflect.Bool:
-		return v.Bool()
+		return v.Bool(), nil
 	case reflect.Struct:
-		if v.Type() == reflect.TypeOf(time.Time{}) {
+		if v.Type() == timeType {
 			t := v.Interface().(time.Time)
 			if t.IsZero() {
-				return ""
+				return "", nil
 			}
-			if fm != nil && fm.TimeFormat != "" {
-				return t.Format(fm.TimeFormat)
+			return formatTime(t, fm, o), nil
+		}
+		if isSQLNull(v.Type()) {
+			inner, valid := sqlNullValue(v)
+			if !valid {
+				return "", nil
 			}
-			// Default time format for write.
-			return t.Format("2006-01-02 15:04:05")
+			return valueToCell(inner, fm, o)
 		}
 	}
 
 	// Fallback: string representation.
-	return fmt.Sprintf("%v", v.Interface())
+	return fmt.Sprintf("%v", v.Interface()), nil
 }
 
-// newStreamWriterCore initializes a StreamWriter that writes to either
-// a file path or an io.Writer, sharing the same Options/typeMeta as read side.
-func newStreamWriterCore[T any](o *Options, out io.Writer, path string) (*StreamWriter[T], error) {
+// rowSink receives the rows produced by a StreamWriter, in increasing row order.
+type rowSink interface {
+	// setRow writes vals starting at column A of the given 1-based row.
+	setRow(row int, vals []any) error
+	// close flushes pending rows and writes the result to its destination.
+	close() error
+}
+
+// xlsxSink is a rowSink writing a single-sheet workbook through excelize's
+// stream writer; the workbook is written to out or saved to path on close.
+type xlsxSink struct {
+	f     *excelize.File
+	sw    *excelize.StreamWriter
+	sheet string
+	out   io.Writer
+	path  string
+}
+
+// newXLSXSink creates a new workbook with the sheet from Options.
+func newXLSXSink(o *Options, out io.Writer, path string) (*xlsxSink, error) {
 	// 1) Create a new workbook.
 	f := excelize.NewFile()
+	if o.Date1904 {
+		if err := setDate1904(f); err != nil {
+			return nil, err
+		}
+	}
 
 	// 2) Resolve sheet name: if SheetName is empty, use default active sheet.
 	sheet := o.SheetName
@@ -1306,48 +2000,83 @@ func newStreamWriterCore[T any](o *Options, out io.Writer, path string) (*Stream
 	if err != nil {
 		return nil, err
 	}
+	return &xlsxSink{f: f, sw: esw, sheet: sheet, out: out, path: path}, nil
+}
+
+func (s *xlsxSink) numFmtStyle(code string) (int, error) { return newNumFmtStyle(s.f, code) }
+
+func (s *xlsxSink) addDropdown(col, fromRow int, labels []string) error {
+	return addDropdown(s.f, s.sheet, col, fromRow, labels)
+}
 
-	// 4) Build type metadata & field order.
+func (s *xlsxSink) setRow(row int, vals []any) error {
+	return s.sw.SetRow(fmt.Sprintf("A%d", row), vals)
+}
+
+func (s *xlsxSink) close() error {
+	if err := s.sw.Flush(); err != nil {
+		return err
+	}
+
+	// Write to appropriate destination.
+	if s.out != nil {
+		return s.f.Write(s.out)
+	}
+	if s.path != "" {
+		return s.f.SaveAs(s.path)
+	}
+	return nil
+}
+
+// newStreamWriterCore initializes a StreamWriter that writes to either
+// a file path or an io.Writer, sharing the same Options/typeMeta as read side.
+// The output format comes from Options, then the path extension (default XLSX).
+func newStreamWriterCore[T any](o *Options, out io.Writer, path string) (*StreamWriter[T], error) {
+	format := o.Format
+	if format == FormatAuto && path != "" {
+		format = formatFromExt(path)
+	}
+	return newStreamWriterWithSink[T](o, func() (rowSink, error) {
+		if format.isText() {
+			return newCSVSink(out, path, format, o)
+		}
+		return newXLSXSink(o, out, path)
+	})
+}
+
+// newStreamWriterWithSink builds the field layout for T, creates the sink via
+// newSink and writes the header row. It is shared by single-file writers and
+// Workbook sheets.
+func newStreamWriterWithSink[T any](o *Options, newSink func() (rowSink, error)) (*StreamWriter[T], error) {
+	// 1) Build type metadata & field order.
 	t := reflect.TypeOf((*T)(nil)).Elem()
 	meta, err := getTypeMeta(t)
 	if err != nil {
 		return nil, err
 	}
+	if err := checkTransforms(meta, o); err != nil {
+		return nil, err
+	}
 	fields, fieldColIndex, maxCol := buildFieldOrderForWrite(meta)
 
+	// 2) Create the sink.
+	sink, err := newSink()
+	if err != nil {
+		return nil, err
+	}
+
 	sw := &StreamWriter[T]{
-		f:             f,
-		sw:            esw,
+		sink:          sink,
 		opts:          o,
 		meta:          meta,
 		fields:        fields,
 		fieldColIndex: fieldColIndex,
 		maxColIndex:   maxCol,
-		out:           out,
-		path:          path,
 		rowBuf:        make([]interface{}, maxCol+1),
+		layoutPending: len(meta.Multi) > 0 || meta.Rest != nil,
 	}
 
-	// 5) Write header row if configured.
-	if o.HeaderRow > 0 {
-		rowVals := sw.rowBuf
-		for i := range rowVals {
-			rowVals[i] = nil
-		}
-		for _, fm := range sw.fields {
-			colIdx := sw.fieldColIndex[fm]
-			if colIdx < 0 || colIdx >= len(rowVals) {
-				continue
-			}
-			rowVals[colIdx] = fieldHeaderName(fm)
-		}
-		axis := fmt.Sprintf("A%d", o.HeaderRow)
-		if err := sw.sw.SetRow(axis, rowVals); err != nil {
-			return nil, err
-		}
-	}
-
-	// 6) Determine first data row.
+	// 3) Determine first data row.
 	if o.FirstDataRow > 0 {
 		sw.curRow = o.FirstDataRow
 	} else if o.HeaderRow > 0 {
@@ -1356,38 +2085,95 @@ func newStreamWriterCore[T any](o *Options, out io.Writer, path string) (*Stream
 		sw.curRow = 1
 	}
 
+	// 4) Write header row if configured (deferred to the first row when the
+	//    width of slice / rest fields depends on the data).
+	if !sw.layoutPending {
+		if err := sw.writeHeader(); err != nil {
+			_ = sw.sink.close()
+			return nil, err
+		}
+	}
+
 	return sw, nil
 }
 
+// writeHeader writes the header row, if configured, after adding the
+// EnumDropdowns validations.
+func (sw *StreamWriter[T]) writeHeader() error {
+	if err := sw.addDropdowns(); err != nil {
+		return err
+	}
+	if sw.opts.HeaderRow <= 0 {
+		return nil
+	}
+	rowVals := sw.rowBuf
+	for i := range rowVals {
+		rowVals[i] = nil
+	}
+	for _, fm := range sw.fields {
+		colIdx := sw.fieldColIndex[fm]
+		if colIdx < 0 || colIdx >= len(rowVals) {
+			continue
+		}
+		rowVals[colIdx] = fieldHeaderName(fm)
+	}
+	for _, l := range sw.multi {
+		for i, c := range l.cols {
+			rowVals[c] = l.headers[i]
+		}
+	}
+	return sw.sink.setRow(sw.opts.HeaderRow, rowVals)
+}
+
+// layout fixes the columns of slice fields and the rest map from samples and
+// writes the pending header row.
+func (sw *StreamWriter[T]) layout(samples []reflect.Value) error {
+	if !sw.layoutPending {
+		return nil
+	}
+	sw.layoutPending = false
+	var maxCol int
+	sw.multi, maxCol = planMultiLayout(sw.meta, samples, sw.maxColIndex)
+	if maxCol > sw.maxColIndex {
+		sw.maxColIndex = maxCol
+		sw.rowBuf = make([]interface{}, maxCol+1)
+	}
+	return sw.writeHeader()
+}
+
+// layoutRows fixes the multi-column layout from all rows, so that every slice
+// element and map key gets a column (used by Write / WriteFile).
+func (sw *StreamWriter[T]) layoutRows(rows []T) error {
+	if !sw.layoutPending {
+		return nil
+	}
+	samples := make([]reflect.Value, len(rows))
+	for i := range rows {
+		samples[i] = reflect.ValueOf(&rows[i]).Elem()
+	}
+	return sw.layout(samples)
+}
+
 // NewStreamWriterFile creates a streaming writer that writes Excel content to a file path.
 // It uses the same Options semantics as the reader side (Sheet, Header, StartRow).
+// A .csv or .tsv extension (or Format(...)) writes delimited text instead.
 func NewStreamWriterFile[T any](path string, opts ...Option) (*StreamWriter[T], error) {
-	var o Options
-	for _, opt := range opts {
-		opt(&o)
-	}
-	applyDefaults(&o)
-	return newStreamWriterCore[T](&o, nil, path)
+	return newStreamWriterCore[T](buildOptions(opts), nil, path)
 }
 
 // NewStreamWriter creates a streaming writer that writes Excel content to an io.Writer,
-// such as an HTTP response or bytes.Buffer.
+// such as an HTTP response or bytes.Buffer. Use Format(FormatCSV) for CSV output.
 func NewStreamWriter[T any](w io.Writer, opts ...Option) (*StreamWriter[T], error) {
 	if w == nil {
 		return nil, fmt.Errorf("excelio: writer must not be nil")
 	}
-	var o Options
-	for _, opt := range opts {
-		opt(&o)
-	}
-	applyDefaults(&o)
-	return newStreamWriterCore[T](&o, w, "")
+	return newStreamWriterCore[T](buildOptions(opts), w, "")
 }
 
 // WriteRow writes a single struct value as one row into the sheet.
 // T is expected to be a struct type (same requirement as the read side).
 func (sw *StreamWriter[T]) WriteRow(obj *T) error {
-	if sw == nil || sw.sw == nil {
+	if sw == nil || sw.sink == nil {
 		return fmt.Errorf("excelio: stream writer is nil")
 	}
 	if obj == nil {
@@ -1395,7 +2181,8 @@ func (sw *StreamWriter[T]) WriteRow(obj *T) error {
 		return nil
 	}
 
-	v := reflect.ValueOf(*obj)
+	// Keep v addressable so pointer-receiver marshalers are found.
+	v := reflect.ValueOf(obj).Elem()
 	if v.Kind() == reflect.Ptr {
 		v = v.Elem()
 	}
@@ -1403,6 +2190,10 @@ func (sw *StreamWriter[T]) WriteRow(obj *T) error {
 		return fmt.Errorf("excelio: WriteRow expects struct type, got %s", v.Kind())
 	}
 
+	if err := sw.layout([]reflect.Value{v}); err != nil {
+		return err
+	}
+
 	rowVals := sw.rowBuf
 	for i := range rowVals {
 		rowVals[i] = nil
@@ -1413,12 +2204,22 @@ func (sw *StreamWriter[T]) WriteRow(obj *T) error {
 		if colIdx < 0 || colIdx >= len(rowVals) {
 			continue
 		}
-		fieldVal := v.FieldByIndex(fm.Index)
-		rowVals[colIdx] = valueToCell(fieldVal, fm)
+		// A nil pointer-to-struct on the path leaves the cell empty.
+		fieldVal, err := v.FieldByIndexErr(fm.Index)
+		if err != nil {
+			continue
+		}
+		cell, err := sw.cell(fieldVal, fm)
+		if err != nil {
+			return fmt.Errorf("excelio: field %s: %w", fm.FieldName, err)
+		}
+		rowVals[colIdx] = cell
+	}
+	if err := writeMulti(v, sw.multi, rowVals, sw.cell); err != nil {
+		return err
 	}
 
-	axis := fmt.Sprintf("A%d", sw.curRow)
-	if err := sw.sw.SetRow(axis, rowVals); err != nil {
+	if err := sw.sink.setRow(sw.curRow, rowVals); err != nil {
 		return err
 	}
 	sw.curRow++
@@ -1427,6 +2228,9 @@ func (sw *StreamWriter[T]) WriteRow(obj *T) error {
 
 // WriteRows writes multiple struct values as subsequent rows.
 func (sw *StreamWriter[T]) WriteRows(objs []T) error {
+	if err := sw.layoutRows(objs); err != nil {
+		return err
+	}
 	for i := range objs {
 		if err := sw.WriteRow(&objs[i]); err != nil {
 			return err
@@ -1435,10 +2239,10 @@ func (sw *StreamWriter[T]) WriteRows(objs []T) error {
 	return nil
 }
 
-// Close flushes the stream and writes/saves the workbook.
+// Close flushes the stream and writes/saves the workbook (or CSV/TSV file).
 // It is safe to call Close multiple times; subsequent calls are no-ops.
 func (sw *StreamWriter[T]) Close() error {
-	if sw == nil || sw.sw == nil {
+	if sw == nil || sw.sink == nil {
 		return nil
 	}
 	if sw.closed {
@@ -1446,18 +2250,11 @@ func (sw *StreamWriter[T]) Close() error {
 	}
 	sw.closed = true
 
-	if err := sw.sw.Flush(); err != nil {
+	if err := sw.layout(nil); err != nil {
+		_ = sw.sink.close()
 		return err
 	}
-
-	// Write to appropriate sink.
-	if sw.out != nil {
-		return sw.f.Write(sw.out)
-	}
-	if sw.path != "" {
-		return sw.f.SaveAs(sw.path)
-	}
-	return nil
+	return sw.sink.close()
 }
 
 // WriteFile writes a slice of structs as Excel rows into a file path using streaming.
@@ -1470,6 +2267,9 @@ func WriteFile[T any](path string, rows []T, opts ...Option) error {
 	}
 	defer sw.Close()
 
+	if err := sw.layoutRows(rows); err != nil {
+		return err
+	}
 	for i := range rows {
 		if err := sw.WriteRow(&rows[i]); err != nil {
 			return err
@@ -1488,6 +2288,9 @@ func Write[T any](w io.Writer, rows []T, opts ...Option) error {
 	}
 	defer sw.Close()
 
+	if err := sw.layoutRows(rows); err != nil {
+		return err
+	}
 	for i := range rows {
 		if err := sw.WriteRow(&rows[i]); err != nil {
 			return err
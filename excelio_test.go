@@ -0,0 +1,303 @@
+package excelio
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// concSource is written as the sheet; concItem reads it back, failing on
+// every Qty that is not a number.
+type concSource struct {
+	Code string `excel:"Code"`
+	Qty  string `excel:"Qty"`
+}
+
+type concItem struct {
+	Code string `excel:"Code" required:"true"`
+	Qty  int    `excel:"Qty"`
+}
+
+const (
+	concRows     = 500
+	concBadEvery = 7 // rows i with i%concBadEvery == 0 have Qty "x"
+)
+
+// concFixture builds a sheet of concRows data rows in format f.
+func concFixture(t testing.TB, f FileFormat) []byte {
+	t.Helper()
+	rows := make([]concSource, concRows)
+	for i := range rows {
+		rows[i] = concSource{Code: fmt.Sprintf("C%04d", i), Qty: strconv.Itoa(i)}
+		if i%concBadEvery == 0 {
+			rows[i].Qty = "x"
+		}
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, rows, Format(f)); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// checkConcResult verifies the rows and errors of one read of concFixture.
+func checkConcResult(items []concItem, errs []RowError) error {
+	want := 0
+	for i := 0; i < concRows; i++ {
+		if i%concBadEvery == 0 {
+			continue
+		}
+		if want >= len(items) {
+			return fmt.Errorf("got %d rows, want more", len(items))
+		}
+		if it := items[want]; it.Code != fmt.Sprintf("C%04d", i) || it.Qty != i {
+			return fmt.Errorf("row %d = %+v, want C%04d/%d", want, it, i, i)
+		}
+		want++
+	}
+	if len(items) != want {
+		return fmt.Errorf("got %d rows, want %d", len(items), want)
+	}
+
+	bad := (concRows + concBadEvery - 1) / concBadEvery
+	if len(errs) != bad {
+		return fmt.Errorf("got %d errors, want %d", len(errs), bad)
+	}
+	for k, e := range errs {
+		i := k * concBadEvery
+		if e.ExcelRowIndex != i+2 || e.LogicalIndex != i+1 || e.ColLetter != "B" ||
+			e.ColIndex != 2 || e.Field != "Qty" || e.Column != "Qty" || e.Value != "x" {
+			return fmt.Errorf("error %d = %+v, want row %d column B", k, e, i+2)
+		}
+	}
+	return nil
+}
+
+// TestConcurrentReadStream hammers Read and Stream of the same type from many
+// goroutines, with and without parallel mapping. Run with -race.
+func TestConcurrentReadStream(t *testing.T) {
+	for _, f := range []FileFormat{FormatXLSX, FormatCSV} {
+		data := concFixture(t, f)
+		t.Run(f.String(), func(t *testing.T) {
+			const goroutines = 8
+			var wg sync.WaitGroup
+			errc := make(chan error, goroutines*4)
+			for g := 0; g < goroutines; g++ {
+				wg.Add(1)
+				go func(g int) {
+					defer wg.Done()
+					for _, workers := range []int{0, 3} {
+						opts := []Option{Format(f), Workers(workers)}
+
+						items, errs, err := Read[concItem](bytes.NewReader(data), opts...)
+						if err == nil {
+							err = checkConcResult(items, errs)
+						}
+						if err != nil {
+							errc <- fmt.Errorf("goroutine %d Read workers=%d: %w", g, workers, err)
+						}
+
+						var streamed []concItem
+						var handled []RowError
+						errs, err = Stream[concItem](bytes.NewReader(data), append(opts,
+							OnStreamRow(func(rowIdx, logicalIdx int, obj *concItem, rowErrs []RowError) error {
+								if obj != nil {
+									streamed = append(streamed, *obj)
+								}
+								handled = append(handled, rowErrs...)
+								return nil
+							}))...)
+						if err == nil {
+							err = checkConcResult(streamed, errs)
+						}
+						if err == nil && len(handled) != len(errs) {
+							err = fmt.Errorf("handler saw %d errors, returned %d", len(handled), len(errs))
+						}
+						if err != nil {
+							errc <- fmt.Errorf("goroutine %d Stream workers=%d: %w", g, workers, err)
+						}
+					}
+				}(g)
+			}
+			wg.Wait()
+			close(errc)
+			for err := range errc {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestExcelSerialToTime(t *testing.T) {
+	day := func(y int, m time.Month, d, h, min, s int) time.Time {
+		return time.Date(y, m, d, h, min, s, 0, time.UTC)
+	}
+	tests := []struct {
+		serial   float64
+		date1904 bool
+		want     time.Time
+		wantErr  bool
+	}{
+		// 1900 date system.
+		{serial: 0, want: day(1899, 12, 31, 0, 0, 0)},
+		{serial: 0.25, want: day(1899, 12, 31, 6, 0, 0)},
+		{serial: 1, want: day(1900, 1, 1, 0, 0, 0)},
+		{serial: 59, want: day(1900, 2, 28, 0, 0, 0)},
+		{serial: 59.5, want: day(1900, 2, 28, 12, 0, 0)},
+		{serial: 60, wantErr: true},
+		{serial: 60.5, wantErr: true},
+		{serial: 61, want: day(1900, 3, 1, 0, 0, 0)},
+		{serial: 45292, want: day(2024, 1, 1, 0, 0, 0)},
+		{serial: 45292.75, want: day(2024, 1, 1, 18, 0, 0)},
+		{serial: 45292 + 1.0/86400, want: day(2024, 1, 1, 0, 0, 1)},
+		{serial: 2958465, want: day(9999, 12, 31, 0, 0, 0)},
+		{serial: 2958465.99999, want: day(9999, 12, 31, 23, 59, 59)},
+		{serial: 2958466, wantErr: true},
+		{serial: -1, wantErr: true},
+		{serial: math.NaN(), wantErr: true},
+
+		// 1904 date system.
+		{serial: 0, date1904: true, want: day(1904, 1, 1, 0, 0, 0)},
+		{serial: 0.5, date1904: true, want: day(1904, 1, 1, 12, 0, 0)},
+		{serial: 1, date1904: true, want: day(1904, 1, 2, 0, 0, 0)},
+		{serial: 59, date1904: true, want: day(1904, 2, 29, 0, 0, 0)},
+		{serial: 60, date1904: true, want: day(1904, 3, 1, 0, 0, 0)},
+		{serial: 61, date1904: true, want: day(1904, 3, 2, 0, 0, 0)},
+		{serial: 43830, date1904: true, want: day(2024, 1, 1, 0, 0, 0)},
+		{serial: 2957003, date1904: true, want: day(9999, 12, 31, 0, 0, 0)},
+		{serial: 2957004, date1904: true, wantErr: true},
+		{serial: -0.5, date1904: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := excelSerialToTime(tt.serial, tt.date1904)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("excelSerialToTime(%v, %v) = %v, want error", tt.serial, tt.date1904, got)
+			}
+			continue
+		}
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("excelSerialToTime(%v, %v) = %v, %v; want %v", tt.serial, tt.date1904, got, err, tt.want)
+		}
+	}
+}
+
+type date1904Row struct {
+	Name string    `excel:"Name"`
+	When time.Time `excel:"When" numfmt:"yyyy-mm-dd"`
+}
+
+func TestDate1904Workbook(t *testing.T) {
+	// Detection: a workbook in the 1904 system whose cell stores serial 0.
+	f := excelize.NewFile()
+	if err := setDate1904(f); err != nil {
+		t.Fatal(err)
+	}
+	sheet := f.GetSheetName(0)
+	_ = f.SetSheetRow(sheet, "A1", &[]any{"Name", "When"})
+	_ = f.SetSheetRow(sheet, "A2", &[]any{"epoch", 0})
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+
+	b, err := openBookReader(bytes.NewReader(buf.Bytes()), &Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	o := buildOptions(nil)
+	rows, err := b.rows(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = rows.Close()
+	_ = b.Close()
+	if !o.Date1904 {
+		t.Fatal("date1904 workbook not detected")
+	}
+
+	got, errs, err := Read[date1904Row](bytes.NewReader(buf.Bytes()), RawValues())
+	if err != nil || len(errs) > 0 || len(got) != 1 {
+		t.Fatalf("Read = %v, %v, %v", got, errs, err)
+	}
+	if want := time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC); !got[0].When.Equal(want) {
+		t.Errorf("When = %v, want %v", got[0].When, want)
+	}
+
+	// Writing: Date1904 sets the workbook property and stores 1904 serials.
+	when := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	buf.Reset()
+	if err := Write(&buf, []date1904Row{{Name: "new year", When: when}}, Date1904()); err != nil {
+		t.Fatal(err)
+	}
+	f, err = excelize.OpenReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	props, err := f.GetWorkbookProps()
+	if err != nil || props.Date1904 == nil || !*props.Date1904 {
+		t.Errorf("workbook props = %+v, %v; want Date1904", props, err)
+	}
+	raw, _ := f.GetCellValue(f.GetSheetName(0), "B2", excelize.Options{RawCellValue: true})
+	if raw != "43830" {
+		t.Errorf("stored serial = %q, want 43830", raw)
+	}
+	_ = f.Close()
+
+	got, errs, err = Read[date1904Row](bytes.NewReader(buf.Bytes()), RawValues())
+	if err != nil || len(errs) > 0 || len(got) != 1 || !got[0].When.Equal(when) {
+		t.Errorf("round trip = %v, %v, %v; want %v", got, errs, err, when)
+	}
+}
+
+func TestStreamBatchRowIndices(t *testing.T) {
+	data := concFixture(t, FormatCSV)
+
+	var items []concItem
+	var rowErrs, aggErrs []RowError
+	var covered []int // data rows covered by each call
+	_, err := Stream[concItem](bytes.NewReader(data), Format(FormatCSV), SumEquals("Qty", -1),
+		OnStreamBatch(64, func(batch []Row[concItem], errs []RowError) error {
+			for _, row := range batch {
+				var i int
+				fmt.Sscanf(row.Value.Code, "C%d", &i)
+				if row.ExcelRowIndex != i+2 || row.LogicalIndex != i+1 {
+					t.Errorf("row %s: ExcelRowIndex %d, LogicalIndex %d; want %d, %d",
+						row.Value.Code, row.ExcelRowIndex, row.LogicalIndex, i+2, i+1)
+				}
+				items = append(items, row.Value)
+			}
+			n := len(batch)
+			for _, e := range errs {
+				if e.ExcelRowIndex == 0 {
+					aggErrs = append(aggErrs, e)
+					continue
+				}
+				rowErrs = append(rowErrs, e)
+				n++
+			}
+			covered = append(covered, n)
+			return nil
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkConcResult(items, rowErrs); err != nil {
+		t.Error(err)
+	}
+	if len(aggErrs) != 1 || aggErrs[0].Field != "Qty" {
+		t.Errorf("aggregate errors = %v, want one for Qty", aggErrs)
+	}
+	// The Aggregate error rides on the last batch without counting as a row.
+	want := []int{64, 64, 64, 64, 64, 64, 64, 52}
+	if fmt.Sprint(covered) != fmt.Sprint(want) {
+		t.Errorf("rows per batch = %v, want %v", covered, want)
+	}
+}
@@ -0,0 +1,274 @@
+package excelio
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+/* =========================================================
+ *  Header normalization, pattern & fuzzy matching
+ * ========================================================= */
+
+// HeaderNorm selects how header texts (and `excel` tag names) are normalized
+// before they are compared. Matching is always case-insensitive.
+type HeaderNorm uint
+
+const (
+	// NormSpace collapses runs of whitespace (including non-breaking spaces)
+	// into one space and drops zero-width characters.
+	NormSpace HeaderNorm = 1 << iota
+	// NormPunct drops punctuation and symbols: "Product Code*" → "product code".
+	NormPunct
+	// NormNFKC applies Unicode NFKC normalization, so compatibility and
+	// composed/decomposed forms (full-width letters, Thai sara am, ...) compare equal.
+	NormNFKC
+	// NormUnderscore treats "_" as a space: "product_code" → "product code".
+	NormUnderscore
+
+	// NormAll enables every normalization.
+	NormAll = NormSpace | NormPunct | NormNFKC | NormUnderscore
+)
+
+// MatchKind tells how a field was bound to its column (see HeaderMatch).
+type MatchKind string
+
+const (
+	MatchByCol        MatchKind = "col"        // `col:"2"` tag
+	MatchByLetter     MatchKind = "excelcol"   // `excelcol:"C"` tag
+	MatchByHeader     MatchKind = "header"     // header text equal to the tag (ignoring case)
+	MatchByNormalized MatchKind = "normalized" // equal after NormalizeHeaders
+	MatchByPattern    MatchKind = "pattern"    // `excelre` tag or MatchHeader predicate
+	MatchByFuzzy      MatchKind = "fuzzy"      // similarity above the FuzzyHeaders threshold
+	MatchByRest       MatchKind = "rest"       // collected by the `excel:",rest"` map
+)
+
+// NormalizeHeaders normalizes header texts and `excel` tag names before
+// matching them, e.g. NormalizeHeaders(excelio.NormAll) binds
+// "Product  Code", "product_code" and "Product Code*" to `excel:"Product Code"`.
+func NormalizeHeaders(n HeaderNorm) Option {
+	return func(o *Options) { o.HeaderNorm = n }
+}
+
+// FuzzyHeaders binds fields that found no exact (or normalized) header to the
+// most similar unused header, if its similarity is at least threshold
+// (0 < threshold <= 1; e.g. 0.8). Similarity is 1 - edit distance / length,
+// computed on fully normalized texts. Use InspectHeaders to review the result.
+func FuzzyHeaders(threshold float64) Option {
+	return func(o *Options) { o.FuzzyThreshold = threshold }
+}
+
+// MatchHeader binds field (its Go name, e.g. "Price" or "Billing.Street") to
+// the column whose header satisfies match, for headers that change over time:
+//
+//	excelio.MatchHeader("Price", func(h string) bool {
+//	    return strings.HasPrefix(h, "Price (")
+//	})
+//
+// The field must be mapped by a tag (usually `excel` with its usual header);
+// match is consulted only if no header equals one of its `excel` names, and
+// takes precedence over an `excelre` tag. Columns already bound to other
+// fields are not considered; if several columns match, the read fails.
+func MatchHeader(field string, match func(header string) bool) Option {
+	return func(o *Options) {
+		if o.headerMatchers == nil {
+			o.headerMatchers = make(map[string]func(string) bool)
+		}
+		o.headerMatchers[field] = match
+	}
+}
+
+// headerMatcherFor returns the MatchHeader predicate or `excelre` pattern of fm.
+func headerMatcherFor(fm *fieldMeta, o *Options) func(string) bool {
+	if o != nil {
+		if fn := o.headerMatchers[fm.Path]; fn != nil {
+			return fn
+		}
+		if fn := o.headerMatchers[fm.FieldName]; fn != nil {
+			return fn
+		}
+	}
+	if fm.HeaderRe != nil {
+		return fm.HeaderRe.MatchString
+	}
+	return nil
+}
+
+// patternBind binds the fields not in fieldColIndex that have a MatchHeader
+// predicate or `excelre` pattern to the single free column they match.
+func patternBind(meta *typeMeta, headerMap map[int]string, fieldColIndex map[*fieldMeta]int, matches map[*fieldMeta]HeaderMatch, o *Options) error {
+	if o != nil {
+		for name := range o.headerMatchers {
+			if meta.FindFieldByName(name) == nil && !hasFieldName(meta, name) {
+				return fmt.Errorf("excelio: MatchHeader: unknown or untagged field %q", name)
+			}
+		}
+	}
+
+	used := make(map[int]bool, len(fieldColIndex))
+	for _, idx := range fieldColIndex {
+		used[idx] = true
+	}
+	for _, fm := range meta.Fields {
+		if _, ok := fieldColIndex[fm]; ok {
+			continue
+		}
+		match := headerMatcherFor(fm, o)
+		if match == nil {
+			continue
+		}
+		found := -1
+		var hits []string
+		for col := 0; col < len(headerMap); col++ {
+			h := headerMap[col]
+			if used[col] || h == "" || !match(h) {
+				continue
+			}
+			if found < 0 {
+				found = col
+			}
+			hits = append(hits, fmt.Sprintf("%s %q", colLetter(col), h))
+		}
+		if len(hits) > 1 {
+			return fmt.Errorf("excelio: field %s: header pattern is ambiguous, matches columns %s",
+				fm.FieldName, strings.Join(hits, ", "))
+		}
+		if found >= 0 {
+			used[found] = true
+			fieldColIndex[fm] = found
+			matches[fm] = HeaderMatch{Header: headerMap[found], By: MatchByPattern, Score: 1}
+		}
+	}
+	return nil
+}
+
+// hasFieldName reports whether meta has a field with the display name name.
+func hasFieldName(meta *typeMeta, name string) bool {
+	for _, fm := range meta.Fields {
+		if fm.FieldName == name {
+			return true
+		}
+	}
+	return false
+}
+
+// headerKey returns the lookup key of a header text or tag name.
+func headerKey(s string, o *Options) string {
+	if o == nil || o.HeaderNorm == 0 {
+		return strings.ToLower(strings.TrimSpace(s))
+	}
+	return normalizeHeader(s, o.HeaderNorm)
+}
+
+// normalizeHeader lowercases s and applies the normalizations selected by n.
+func normalizeHeader(s string, n HeaderNorm) string {
+	if n&NormNFKC != 0 {
+		s = norm.NFKC.String(s)
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	pendingSpace := false
+	for _, r := range s {
+		if n&NormUnderscore != 0 && r == '_' {
+			r = ' '
+		}
+		switch {
+		case n&NormSpace != 0 && unicode.Is(unicode.Cf, r):
+			continue // zero-width space / joiner, BOM, soft hyphen, ...
+		case n&NormPunct != 0 && (unicode.IsPunct(r) || unicode.IsSymbol(r)):
+			continue
+		case n&NormSpace != 0 && unicode.IsSpace(r):
+			pendingSpace = b.Len() > 0
+			continue
+		}
+		if pendingSpace {
+			b.WriteByte(' ')
+			pendingSpace = false
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// similarity returns 1 - levenshtein(a, b) / max(len(a), len(b)), over runes.
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 && len(rb) == 0 {
+		return 1
+	}
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return 1 - float64(prev[len(rb)])/float64(max(len(ra), len(rb)))
+}
+
+// fuzzyBind binds the header fields of meta that are not in fieldColIndex to
+// unused header columns whose similarity reaches o.FuzzyThreshold. Best scores
+// are assigned first; each column is used at most once.
+func fuzzyBind(meta *typeMeta, headerMap map[int]string, fieldColIndex map[*fieldMeta]int, matches map[*fieldMeta]HeaderMatch, o *Options) {
+	used := make(map[int]bool, len(fieldColIndex))
+	for _, idx := range fieldColIndex {
+		used[idx] = true
+	}
+
+	type candidate struct {
+		fieldPos int
+		fm       *fieldMeta
+		col      int
+		score    float64
+	}
+	var cands []candidate
+	for pos, fm := range meta.Fields {
+		if _, ok := fieldColIndex[fm]; ok || len(fm.ColumnNames) == 0 {
+			continue
+		}
+		for col, h := range headerMap {
+			if used[col] || h == "" {
+				continue
+			}
+			hk := normalizeHeader(h, NormAll)
+			best := 0.0
+			for _, name := range fm.ColumnNames {
+				best = max(best, similarity(normalizeHeader(name, NormAll), hk))
+			}
+			if best >= o.FuzzyThreshold {
+				cands = append(cands, candidate{pos, fm, col, best})
+			}
+		}
+	}
+	sort.Slice(cands, func(i, j int) bool {
+		a, b := cands[i], cands[j]
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		if a.fieldPos != b.fieldPos {
+			return a.fieldPos < b.fieldPos
+		}
+		return a.col < b.col
+	})
+
+	for _, c := range cands {
+		if _, ok := fieldColIndex[c.fm]; ok || used[c.col] {
+			continue
+		}
+		used[c.col] = true
+		fieldColIndex[c.fm] = c.col
+		matches[c.fm] = HeaderMatch{Header: headerMap[c.col], By: MatchByFuzzy, Score: c.score}
+	}
+}
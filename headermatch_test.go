@@ -0,0 +1,183 @@
+package excelio
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeHeader(t *testing.T) {
+	tests := []struct {
+		in   string
+		n    HeaderNorm
+		want string
+	}{
+		{"Product  Code", NormSpace, "product code"},
+		{"Product\u00a0Code", NormSpace, "product code"}, // non-breaking space
+		{" \tProduct \n Code ", NormSpace, "product code"},
+		{"Product\u200bCode", NormSpace, "productcode"}, // zero-width space
+		{"product_code", NormUnderscore, "product code"},
+		{"product__code", NormUnderscore | NormSpace, "product code"},
+		{"Product Code*", NormPunct, "product code"},
+		{"Price (THB)", NormPunct, "price thb"},
+		{"Ｐｒｏｄｕｃｔ Ｃｏｄｅ", NormNFKC, "product code"},
+		{"ราคา", NormNFKC, "ราคา"},
+		{"Product  Code*", 0, "product  code*"},
+		{"__Product  Code*__", NormAll, "product code"},
+	}
+	for _, tt := range tests {
+		if got := normalizeHeader(tt.in, tt.n); got != tt.want {
+			t.Errorf("normalizeHeader(%q, %b) = %q, want %q", tt.in, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"", "", 1},
+		{"code", "code", 1},
+		{"code", "", 0},
+		{"kitten", "sitting", 1 - 3.0/7},
+		{"product code", "prodcut code", 1 - 2.0/12},
+		{"ราคา", "ราคาา", 1 - 1.0/5}, // runes, not bytes
+	}
+	for _, tt := range tests {
+		if got := similarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("similarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+type matchRow struct {
+	Code  string  `excel:"Product Code"`
+	Price float64 `excel:"Unit Price"`
+	Qty   int     `excel:"Qty"`
+}
+
+// matchKinds returns field -> how it was bound.
+func matchKinds(r HeaderReport) map[string]MatchKind {
+	m := make(map[string]MatchKind, len(r.Matched))
+	for _, hm := range r.Matched {
+		m[hm.Field] = hm.By
+	}
+	return m
+}
+
+func TestNormalizeHeadersRead(t *testing.T) {
+	src := "Product  Code*,unit_price,QTY\nP1,1.5,2\n"
+	opts := []Option{Format(FormatCSV), NormalizeHeaders(NormAll)}
+	items, errs, err := Read[matchRow](strings.NewReader(src), opts...)
+	if err != nil || len(errs) > 0 || len(items) != 1 || items[0] != (matchRow{"P1", 1.5, 2}) {
+		t.Fatalf("Read = %+v, %v, %v", items, errs, err)
+	}
+
+	r, err := InspectHeaders[matchRow](strings.NewReader(src), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := matchKinds(r)
+	if got["Code"] != MatchByNormalized || got["Price"] != MatchByNormalized || got["Qty"] != MatchByHeader {
+		t.Errorf("matches = %v", got)
+	}
+
+	// Without normalization only Qty binds.
+	r, err = InspectHeaders[matchRow](strings.NewReader(src), Format(FormatCSV))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Matched) != 1 || len(r.Missing) != 2 {
+		t.Errorf("without NormalizeHeaders: matched %v, missing %v", r.Matched, r.Missing)
+	}
+}
+
+func TestFuzzyHeaders(t *testing.T) {
+	// "Prodcut Code" is a typo; "Unit Prices" and "Unit Price Old" compete
+	// for Unit Price, and the closer one wins; "Quantity" is too far from Qty.
+	src := "Prodcut Code,Unit Price Old,Unit Prices,Quantity\nP1,9,1.5,2\n"
+	r, err := InspectHeaders[matchRow](strings.NewReader(src), Format(FormatCSV), FuzzyHeaders(0.8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	byField := make(map[string]HeaderMatch)
+	for _, m := range r.Matched {
+		byField[m.Field] = m
+	}
+	if m := byField["Code"]; m.By != MatchByFuzzy || m.Header != "Prodcut Code" || math.Abs(m.Score-(1-2.0/12)) > 1e-9 {
+		t.Errorf("Code = %+v", m)
+	}
+	if m := byField["Price"]; m.By != MatchByFuzzy || m.Header != "Unit Prices" {
+		t.Errorf("Price = %+v", m)
+	}
+	if _, ok := byField["Qty"]; ok || len(r.Missing) != 1 || r.Missing[0] != "Qty" {
+		t.Errorf("Qty bound or not missing: %+v, missing %v", byField["Qty"], r.Missing)
+	}
+
+	items, _, err := Read[matchRow](strings.NewReader(src), Format(FormatCSV), FuzzyHeaders(0.8))
+	if err != nil || len(items) != 1 || items[0] != (matchRow{Code: "P1", Price: 1.5}) {
+		t.Errorf("Read = %+v, %v", items, err)
+	}
+}
+
+type patternRow struct {
+	Code  string  `excel:"Code"`
+	Price float64 `excel:"Price" excelre:"^Price \\("`
+	Tax   float64 `excelre:"(?i)^vat"`
+}
+
+func TestPatternBind(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		opts    []Option
+		want    map[string]string // field -> bound header
+		wantErr string
+	}{
+		{name: "exact name first", header: "Code,Price (THB),Price,VAT 7%",
+			want: map[string]string{"Code": "Code", "Price": "Price", "Tax": "VAT 7%"}},
+		{name: "excelre", header: "Code,Price (THB),vat",
+			want: map[string]string{"Code": "Code", "Price": "Price (THB)", "Tax": "vat"}},
+		{name: "bound columns are skipped", header: "Code,Price (THB),VAT (7%)",
+			opts: []Option{MatchHeader("Tax", func(h string) bool { return strings.Contains(h, "(") })},
+			want: map[string]string{"Code": "Code", "Price": "Price (THB)", "Tax": "VAT (7%)"}},
+		{name: "MatchHeader over excelre", header: "Code,Price (THB),Cost,VAT",
+			opts: []Option{MatchHeader("Price", func(h string) bool { return h == "Cost" })},
+			want: map[string]string{"Code": "Code", "Price": "Cost", "Tax": "VAT"}},
+		{name: "ambiguous", header: "Code,Price (THB),Price (USD)",
+			wantErr: `field Price: header pattern is ambiguous, matches columns B "Price (THB)", C "Price (USD)"`},
+		{name: "unknown MatchHeader field", header: "Code",
+			opts:    []Option{MatchHeader("Cost", func(string) bool { return true })},
+			wantErr: `MatchHeader: unknown or untagged field "Cost"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := InspectHeaders[patternRow](strings.NewReader(tt.header+"\n"), append(tt.opts, Format(FormatCSV))...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make(map[string]string)
+			for _, m := range r.Matched {
+				got[m.Field] = m.Header
+				if m.Field != "Code" && m.Header != m.Field && m.By != MatchByPattern {
+					t.Errorf("%s bound by %s, want pattern", m.Field, m.By)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("matched %v, want %v", got, tt.want)
+			}
+			for f, h := range tt.want {
+				if got[f] != h {
+					t.Errorf("%s bound to %q, want %q", f, got[f], h)
+				}
+			}
+		})
+	}
+}
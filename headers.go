@@ -0,0 +1,186 @@
+package excelio
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+/* =========================================================
+ *  Header validation & inspection
+ * ========================================================= */
+
+// HeaderMatch describes the column a struct field was bound to.
+type HeaderMatch struct {
+	Field     string    // Struct field, e.g. "Code" or "Billing.Street"
+	Header    string    // Header text of the bound column ("" if bound by col / excelcol)
+	ColIndex  int       // 1-based column index
+	ColLetter string    // Column letter, e.g. "C"
+	By        MatchKind // How the column was found
+	Score     float64   // Similarity for MatchByFuzzy; 1 otherwise
+}
+
+// HeaderReport describes how the header row of a sheet was matched against
+// the tags of a struct type. It is returned by InspectHeaders and carried by
+// the *HeaderError of StrictHeaders.
+type HeaderReport struct {
+	Sheet     string        // Resolved sheet name ("" for CSV/TSV)
+	HeaderRow int           // Header row index (1-based)
+	Headers   []string      // Header cells as read (trimmed), by column
+	Matched   []HeaderMatch // Bound fields, in struct order
+	Missing   []string      // Expected headers (or `excelre` patterns) that matched no column
+	Unknown   []string      // Non-empty headers not bound to any field
+	Duplicate []string      // Headers that appear in more than one column
+}
+
+// Err returns a *HeaderError if the report has missing, unknown or duplicate
+// headers, or nil if the header row matches the struct exactly.
+func (r HeaderReport) Err() error {
+	if len(r.Missing) == 0 && len(r.Unknown) == 0 && len(r.Duplicate) == 0 {
+		return nil
+	}
+	return &HeaderError{Report: r}
+}
+
+// HeaderError is returned by reads with StrictHeaders() when the header row
+// does not match the struct. Use errors.As to get the details.
+type HeaderError struct {
+	Report HeaderReport
+}
+
+func (e *HeaderError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "excelio: header row %d", e.Report.HeaderRow)
+	if e.Report.Sheet != "" {
+		fmt.Fprintf(&b, " of sheet %q", e.Report.Sheet)
+	}
+	b.WriteString(" does not match:")
+	list := func(label string, names []string) {
+		if len(names) > 0 {
+			fmt.Fprintf(&b, " %s %s;", label, quoteList(names))
+		}
+	}
+	list("missing", e.Report.Missing)
+	list("unknown", e.Report.Unknown)
+	list("duplicate", e.Report.Duplicate)
+	return strings.TrimSuffix(b.String(), ";")
+}
+
+// quoteList formats names as "a", "b", "c".
+func quoteList(names []string) string {
+	q := make([]string, len(names))
+	for i, n := range names {
+		q[i] = fmt.Sprintf("%q", n)
+	}
+	return strings.Join(q, ", ")
+}
+
+// StrictHeaders makes Read / Stream fail with a *HeaderError before the first
+// data row when the header row has missing headers (any `excel` or `excelre`
+// field without a matching column), unknown extra headers, or duplicate headers.
+func StrictHeaders() Option {
+	return func(o *Options) { o.StrictHeaders = true }
+}
+
+// buildHeaderReport compares the parsed header row with the resolved bindings.
+func buildHeaderReport(meta *typeMeta, headerMap map[int]string, fieldColIndex map[*fieldMeta]int, multi *multiColumns, matches []HeaderMatch, o *Options) HeaderReport {
+	r := HeaderReport{Sheet: o.sheetResolved, HeaderRow: o.HeaderRow}
+
+	r.Headers = make([]string, len(headerMap))
+	for i, h := range headerMap {
+		r.Headers[i] = h
+	}
+
+	r.Matched = matches
+	bound := make(map[int]bool, len(fieldColIndex))
+	for _, m := range matches {
+		bound[m.ColIndex-1] = true
+	}
+	for _, fm := range meta.Fields {
+		idx, ok := fieldColIndex[fm]
+		if !ok {
+			switch {
+			case len(fm.ColumnNames) > 0:
+				r.Missing = append(r.Missing, fm.ColumnNames[0])
+			case fm.HeaderRe != nil:
+				r.Missing = append(r.Missing, fm.HeaderRe.String())
+			}
+			continue
+		}
+		bound[idx] = true
+	}
+	for _, fm := range meta.Multi {
+		if multi == nil || !multi.boundColumns(fm) {
+			r.Missing = append(r.Missing, multiFieldLabel(fm))
+		}
+	}
+
+	seen := make(map[string]int, len(r.Headers))
+	for i, h := range r.Headers {
+		if h == "" {
+			continue
+		}
+		key := headerKey(h, o)
+		seen[key]++
+		if seen[key] == 2 {
+			r.Duplicate = append(r.Duplicate, h)
+		}
+		if !bound[i] {
+			r.Unknown = append(r.Unknown, h)
+		}
+	}
+	return r
+}
+
+// inspectBook reads the header row(s) of the selected sheet and reports how
+// they bind to the fields of T.
+func inspectBook[T any](b book, o *Options) (HeaderReport, error) {
+	if o.HeaderRow <= 0 {
+		return HeaderReport{}, fmt.Errorf("excelio: InspectHeaders needs a header row")
+	}
+	o.StrictHeaders = false // report mismatches instead of failing
+
+	sc, rows, err := newSheetScanner[T](b, o)
+	if err != nil {
+		return HeaderReport{}, err
+	}
+	defer rows.Close()
+
+	// Stop at the first data row after the header.
+	err = sc.produce(rows, func(rr rawRow) error {
+		if rr.readErr != nil {
+			return nil
+		}
+		return errScanStopped
+	})
+	if err != nil && err != errScanStopped {
+		return HeaderReport{}, err
+	}
+	return sc.report(), nil
+}
+
+// InspectHeadersFile reads only the header row of the file at path and reports
+// which columns the fields of T bind to, and which headers are missing,
+// unknown or duplicated. Sheet / Header / Format options apply as for ReadFile.
+func InspectHeadersFile[T any](path string, opts ...Option) (HeaderReport, error) {
+	o := buildOptions(opts)
+	b, err := openBookFile(path, o)
+	if err != nil {
+		return HeaderReport{}, err
+	}
+	defer b.Close()
+
+	return inspectBook[T](b, o)
+}
+
+// InspectHeaders is like InspectHeadersFile but reads from an io.Reader.
+func InspectHeaders[T any](r io.Reader, opts ...Option) (HeaderReport, error) {
+	o := buildOptions(opts)
+	b, err := openBookReader(r, o)
+	if err != nil {
+		return HeaderReport{}, err
+	}
+	defer b.Close()
+
+	return inspectBook[T](b, o)
+}
@@ -0,0 +1,83 @@
+package excelio
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type strictRow struct {
+	Code  string  `excel:"Code"`
+	Name  string  `excel:"Name"`
+	Price float64 `excel:"Price"`
+	Tax   float64 `excelre:"^VAT"`
+}
+
+func TestInspectHeaders(t *testing.T) {
+	tests := []struct {
+		name                        string
+		header                      string
+		missing, unknown, duplicate []string
+	}{
+		{name: "exact", header: "Code,Name,Price,VAT 7%"},
+		{name: "order and case do not matter", header: "price,VAT,name,CODE"},
+		{name: "missing", header: "Code,Price", missing: []string{"Name", "^VAT"}},
+		{name: "unknown", header: "Code,Name,Price,VAT,Remark,,Owner", unknown: []string{"Remark", "Owner"}},
+		{name: "duplicate", header: "Code,Name,Price,VAT,code", unknown: []string{"code"}, duplicate: []string{"code"}},
+		{name: "all", header: "Code,Price,Price,Memo", missing: []string{"Name", "^VAT"},
+			unknown: []string{"Price", "Memo"}, duplicate: []string{"Price"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := InspectHeaders[strictRow](strings.NewReader(tt.header+"\n"), Format(FormatCSV))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r.HeaderRow != 1 || len(r.Headers) != len(strings.Split(tt.header, ",")) {
+				t.Errorf("HeaderRow %d, Headers %q", r.HeaderRow, r.Headers)
+			}
+			for _, l := range []struct {
+				name      string
+				got, want []string
+			}{
+				{"Missing", r.Missing, tt.missing},
+				{"Unknown", r.Unknown, tt.unknown},
+				{"Duplicate", r.Duplicate, tt.duplicate},
+			} {
+				if !reflect.DeepEqual(l.got, l.want) {
+					t.Errorf("%s = %q, want %q", l.name, l.got, l.want)
+				}
+			}
+			if clean := tt.missing == nil && tt.unknown == nil && tt.duplicate == nil; (r.Err() == nil) != clean {
+				t.Errorf("Err() = %v", r.Err())
+			}
+		})
+	}
+}
+
+func TestStrictHeaders(t *testing.T) {
+	src := "Code,Price,Price,Memo\nP1,1,2,x\n"
+	items, errs, err := Read[strictRow](strings.NewReader(src), Format(FormatCSV), StrictHeaders())
+	var he *HeaderError
+	if !errors.As(err, &he) {
+		t.Fatalf("Read = %v, %v, %v; want a *HeaderError", items, errs, err)
+	}
+	if len(items) > 0 || len(errs) > 0 {
+		t.Errorf("rows delivered before the header error: %v, %v", items, errs)
+	}
+	r := he.Report
+	if !reflect.DeepEqual(r.Missing, []string{"Name", "^VAT"}) || !reflect.DeepEqual(r.Duplicate, []string{"Price"}) {
+		t.Errorf("report = %+v", r)
+	}
+	want := `excelio: header row 1 does not match: missing "Name", "^VAT"; unknown "Price", "Memo"; duplicate "Price"`
+	if err.Error() != want {
+		t.Errorf("err = %q, want %q", err, want)
+	}
+
+	// A matching header reads normally.
+	items, errs, err = Read[strictRow](strings.NewReader("Code,Name,Price,VAT\nP1,Pen,1,0.07\n"), Format(FormatCSV), StrictHeaders())
+	if err != nil || len(errs) > 0 || len(items) != 1 {
+		t.Errorf("Read = %v, %v, %v", items, errs, err)
+	}
+}
@@ -0,0 +1,87 @@
+package excelio
+
+import (
+	"errors"
+	"io"
+	"iter"
+)
+
+/* =========================================================
+ *  Iterator API (range-over-func)
+ * ========================================================= */
+
+// Row is one data row yielded by Rows / RowsFile.
+type Row[T any] struct {
+	Value         T          // Mapped value; only meaningful when OK() is true
+	ExcelRowIndex int        // Physical row index in Excel (1-based), 0 for Aggregate errors
+	LogicalIndex  int        // Logical data index (1,2,3,...), -1 for unreadable rows, 0 for Aggregate errors
+	Errors        []RowError // Conversion / validation errors of this row
+}
+
+// OK reports whether the row was mapped without errors.
+func (r Row[T]) OK() bool { return len(r.Errors) == 0 }
+
+// errIterStopped signals scanSheet that the range loop was exited early.
+var errIterStopped = errors.New("excelio: iteration stopped")
+
+// RowsFile returns an iterator over the data rows of the file at path:
+//
+//	for row, err := range excelio.RowsFile[Product]("products.xlsx", excelio.Sheet("Products")) {
+//	    if err != nil {
+//	        return err // fatal: file, sheet or header problem
+//	    }
+//	    if !row.OK() {
+//	        log.Printf("row %d: %v", row.ExcelRowIndex, row.Errors)
+//	        continue
+//	    }
+//	    db.Insert(row.Value)
+//	}
+//
+// The file is opened when the loop starts and closed when it ends, including
+// on break. A fatal error is yielded once, with a zero Row, and ends the loop.
+// Invalid rows are yielded with their errors and a nil error. Errors of
+// Aggregate rules come last, as one Row with ExcelRowIndex and LogicalIndex 0.
+func RowsFile[T any](path string, opts ...Option) iter.Seq2[Row[T], error] {
+	return func(yield func(Row[T], error) bool) {
+		o := buildOptions(opts)
+		b, err := openBookFile(path, o)
+		if err != nil {
+			yield(Row[T]{}, err)
+			return
+		}
+		defer b.Close()
+		iterBook(b, o, yield)
+	}
+}
+
+// Rows is like RowsFile but reads from an io.Reader. The reader is consumed by
+// the first loop, so the iterator can only be ranged over once.
+func Rows[T any](r io.Reader, opts ...Option) iter.Seq2[Row[T], error] {
+	return func(yield func(Row[T], error) bool) {
+		o := buildOptions(opts)
+		b, err := openBookReader(r, o)
+		if err != nil {
+			yield(Row[T]{}, err)
+			return
+		}
+		defer b.Close()
+		iterBook(b, o, yield)
+	}
+}
+
+// iterBook feeds every row of the selected sheet to yield until it returns false.
+func iterBook[T any](b book, o *Options, yield func(Row[T], error) bool) {
+	err := scanSheet(b, o, func(rowIdx, logicalIdx int, obj T, rowErrs []RowError, ok bool) error {
+		row := Row[T]{ExcelRowIndex: rowIdx, LogicalIndex: logicalIdx, Errors: rowErrs}
+		if ok {
+			row.Value = obj
+		}
+		if !yield(row, nil) {
+			return errIterStopped
+		}
+		return nil
+	})
+	if err != nil && err != errIterStopped {
+		yield(Row[T]{}, err)
+	}
+}
@@ -0,0 +1,414 @@
+package excelio
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/* =========================================================
+ *  Multi-column fields: []T and the ",rest" map
+ * ========================================================= */
+
+// A []T field spans several columns, chosen by one of:
+//
+//	Sizes  []int             `excel:"Size {n}"`          // "Size 1", "Size 2", ... ordered by n
+//	Prices []float64         `excelre:"^Price "`         // every matching header, left to right
+//	Marks  []string          `excelcol:"D:H"`            // a fixed column range
+//	Dims   []float64         `excel:"Width,Height,Depth"` // one element per listed header
+//
+// A map[string]string field tagged `excel:",rest"` collects every column with
+// a header that no other field is bound to, keyed by header text.
+
+// restTag marks the catch-all map field.
+const restTag = ",rest"
+
+// nPlaceholder is replaced by the element number in `excel:"Size {n}"`.
+const nPlaceholder = "{n}"
+
+var stringMapType = reflect.TypeOf(map[string]string{})
+
+// isSliceField reports whether t is a multi-column slice ([]byte is a scalar).
+func isSliceField(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8
+}
+
+// parseMultiTags fills the multi-column settings of a slice field.
+func parseMultiTags(fm *fieldMeta, excelColTag string) error {
+	fm.Slice = true
+	if fm.ColIndexTag >= 0 || (excelColTag != "" && !strings.Contains(excelColTag, ":")) {
+		return fmt.Errorf("excelio: field %s: a slice field needs a column range like excelcol:\"D:H\"", fm.FieldName)
+	}
+	if from, to, ok := strings.Cut(excelColTag, ":"); ok {
+		fm.ColFrom = colIndexFromLetter(from)
+		fm.ColTo = colIndexFromLetter(to)
+		if fm.ColFrom < 0 || fm.ColTo < fm.ColFrom {
+			return fmt.Errorf("excelio: field %s: invalid excelcol range %q", fm.FieldName, excelColTag)
+		}
+		fm.ColLetterTag = ""
+	}
+	if len(fm.ColumnNames) == 1 && strings.Contains(fm.ColumnNames[0], nPlaceholder) {
+		tmpl := fm.ColumnNames[0]
+		before, after, _ := strings.Cut(tmpl, nPlaceholder)
+		fm.NameTmpl = tmpl
+		fm.NameRe = regexp.MustCompile(`(?i)^` + regexp.QuoteMeta(strings.TrimSpace(before)) +
+			`\s*(\d+)\s*` + regexp.QuoteMeta(strings.TrimSpace(after)) + `$`)
+		fm.ColumnNames = nil
+	}
+	return nil
+}
+
+// multiColumns holds the per-read columns of slice fields and the rest map.
+type multiColumns struct {
+	slices map[*fieldMeta][]int // element i of the field is read from column slices[fm][i] (-1 = none)
+	rest   []int                // columns collected by the ",rest" map
+}
+
+// bindMultiColumns resolves the columns of the slice fields and the rest map
+// of meta, skipping columns already bound in fieldColIndex. It returns nil if
+// meta has no multi-column fields.
+func bindMultiColumns(meta *typeMeta, headerMap map[int]string, headerIndex map[string]int, fieldColIndex map[*fieldMeta]int, o *Options) (*multiColumns, []HeaderMatch) {
+	if len(meta.Multi) == 0 && meta.Rest == nil {
+		return nil, nil
+	}
+	mc := &multiColumns{slices: make(map[*fieldMeta][]int, len(meta.Multi))}
+	var matches []HeaderMatch
+
+	used := make(map[int]bool, len(fieldColIndex))
+	for _, idx := range fieldColIndex {
+		used[idx] = true
+	}
+
+	for _, fm := range meta.Multi {
+		var cols []int
+		by := MatchByHeader
+		switch {
+		case fm.ColFrom >= 0:
+			by = MatchByLetter
+			for c := fm.ColFrom; c <= fm.ColTo; c++ {
+				cols = append(cols, c)
+			}
+		case fm.NameRe != nil:
+			by = MatchByPattern
+			type numbered struct{ n, col int }
+			var found []numbered
+			for col := 0; col < len(headerMap); col++ {
+				if used[col] {
+					continue
+				}
+				if sm := fm.NameRe.FindStringSubmatch(headerMap[col]); sm != nil {
+					n, _ := strconv.Atoi(sm[1])
+					found = append(found, numbered{n, col})
+				}
+			}
+			sort.SliceStable(found, func(i, j int) bool { return found[i].n < found[j].n })
+			for _, f := range found {
+				cols = append(cols, f.col)
+			}
+		case headerMatcherFor(fm, o) != nil:
+			by = MatchByPattern
+			match := headerMatcherFor(fm, o)
+			for col := 0; col < len(headerMap); col++ {
+				if h := headerMap[col]; !used[col] && h != "" && match(h) {
+					cols = append(cols, col)
+				}
+			}
+		default:
+			for _, name := range fm.ColumnNames {
+				idx, ok := headerIndex[headerKey(name, o)]
+				if !ok || used[idx] {
+					idx = -1
+				}
+				cols = append(cols, idx)
+			}
+		}
+
+		for i, c := range cols {
+			if c < 0 {
+				continue
+			}
+			used[c] = true
+			matches = append(matches, HeaderMatch{
+				Field: fmt.Sprintf("%s[%d]", fm.FieldName, i), Header: headerMap[c],
+				ColIndex: c + 1, ColLetter: colLetter(c), By: by, Score: 1,
+			})
+		}
+		mc.slices[fm] = cols
+	}
+
+	if meta.Rest != nil {
+		for col := 0; col < len(headerMap); col++ {
+			if h := headerMap[col]; h != "" && !used[col] {
+				mc.rest = append(mc.rest, col)
+				matches = append(matches, HeaderMatch{
+					Field: fmt.Sprintf("%s[%s]", meta.Rest.FieldName, h), Header: h,
+					ColIndex: col + 1, ColLetter: colLetter(col), By: MatchByRest, Score: 1,
+				})
+			}
+		}
+	}
+	return mc, matches
+}
+
+// boundColumns reports whether fm is bound to at least one column.
+func (mc *multiColumns) boundColumns(fm *fieldMeta) bool {
+	for _, c := range mc.slices[fm] {
+		if c >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// mapMulti fills the slice fields and the rest map of v from cols.
+func mapMulti(v reflect.Value, meta *typeMeta, mc *multiColumns, headerMap map[int]string, o *Options, rowIdx, logicalIdx int, cols []string, kinds []cellKind) []RowError {
+	var rowErrs []RowError
+	for _, fm := range meta.Multi {
+		fcols := mc.slices[fm]
+		vals := make([]string, len(fcols))
+		for i, c := range fcols {
+			vals[i] = readTransform(cellAt(cols, c), fm, o)
+		}
+
+		// Trailing empty cells do not produce elements.
+		n := len(vals)
+		for n > 0 && strings.TrimSpace(vals[n-1]) == "" {
+			n--
+		}
+		if n == 0 {
+			if fm.Required {
+				rowErrs = append(rowErrs, buildRowError(rowIdx, logicalIdx, fm, -1, headerMap, cols,
+					fmt.Errorf("required value is empty")))
+			}
+			continue
+		}
+
+		field := fieldForSet(v, fm.Index)
+		if !field.CanSet() {
+			continue
+		}
+		slice := reflect.MakeSlice(field.Type(), n, n)
+		for i := 0; i < n; i++ {
+			raw := vals[i]
+			if strings.TrimSpace(raw) == "" {
+				continue
+			}
+			if err := setFieldValue(slice.Index(i), fm, raw, o, kindAt(kinds, fcols[i])); err != nil {
+				rowErrs = append(rowErrs, buildRowError(rowIdx, logicalIdx, fm, fcols[i], headerMap, cols, err))
+			}
+		}
+		field.Set(slice)
+	}
+
+	if fm := meta.Rest; fm != nil && len(mc.rest) > 0 {
+		field := fieldForSet(v, fm.Index)
+		if field.CanSet() {
+			m := make(map[string]string, len(mc.rest))
+			for _, c := range mc.rest {
+				m[headerMap[c]] = readTransform(cellAt(cols, c), fm, o)
+			}
+			field.Set(reflect.ValueOf(m).Convert(field.Type()))
+		}
+	}
+	return rowErrs
+}
+
+// multiFieldLabel describes the expected columns of a slice field in reports.
+func multiFieldLabel(fm *fieldMeta) string {
+	switch {
+	case fm.NameTmpl != "":
+		return fm.NameTmpl
+	case fm.HeaderRe != nil:
+		return fm.HeaderRe.String()
+	case len(fm.ColumnNames) > 0:
+		return fm.ColumnNames[0]
+	}
+	return fm.FieldName
+}
+
+// cellAt returns cols[i], or "" if i is out of range.
+func cellAt(cols []string, i int) string {
+	if i < 0 || i >= len(cols) {
+		return ""
+	}
+	return cols[i]
+}
+
+// sliceColumn returns the column of element i of a slice field, or -1.
+func (mc *multiColumns) sliceColumn(fm *fieldMeta, i int) int {
+	if mc == nil || i < 0 || i >= len(mc.slices[fm]) {
+		return -1
+	}
+	return mc.slices[fm][i]
+}
+
+// splitElementPath splits a validator path like "Sizes[2]" into "Sizes" and 2.
+func splitElementPath(ns string) (string, int, bool) {
+	if !strings.HasSuffix(ns, "]") {
+		return ns, -1, false
+	}
+	open := strings.LastIndexByte(ns, '[')
+	if open < 0 {
+		return ns, -1, false
+	}
+	i, err := strconv.Atoi(ns[open+1 : len(ns)-1])
+	if err != nil {
+		return ns[:open], -1, true
+	}
+	return ns[:open], i, true
+}
+
+/* =========================================================
+ *  Writer layout for multi-column fields
+ * ========================================================= */
+
+// multiLayout places the elements of one slice field (or the rest map keys)
+// in output columns.
+type multiLayout struct {
+	fm      *fieldMeta
+	cols    []int
+	headers []string
+	keys    []string // rest map keys, parallel to cols
+}
+
+// reservedWriteColumns returns the columns of fixed `excelcol:"D:H"` ranges,
+// which auto-assigned scalar fields must not use.
+func reservedWriteColumns(meta *typeMeta) map[int]bool {
+	used := map[int]bool{}
+	for _, fm := range meta.Multi {
+		for c := fm.ColFrom; fm.ColFrom >= 0 && c <= fm.ColTo; c++ {
+			used[c] = true
+		}
+	}
+	return used
+}
+
+// elementHeader returns the header written for element i of a slice field.
+func elementHeader(fm *fieldMeta, i int) string {
+	switch {
+	case fm.NameTmpl != "":
+		return strings.Replace(fm.NameTmpl, nPlaceholder, strconv.Itoa(i+1), 1)
+	case i < len(fm.ColumnNames):
+		return fm.ColumnNames[i]
+	case fm.HeaderRe != nil:
+		// Use the literal start of the pattern if the result still matches it,
+		// so the file reads back: `excelre:"^Price "` → "Price 1".
+		if re, err := regexp.Compile(strings.TrimPrefix(fm.HeaderRe.String(), "^")); err == nil {
+			prefix, _ := re.LiteralPrefix()
+			if h := prefix + strconv.Itoa(i+1); prefix != "" && fm.HeaderRe.MatchString(h) {
+				return h
+			}
+		}
+	}
+	return fmt.Sprintf("%s %d", fm.FieldName, i+1)
+}
+
+// planMultiLayout lays out the slice fields and rest map of meta after column
+// maxCol, sized from samples (the first row for StreamWriter, every row for
+// Write / WriteFile). Fixed ranges and header lists keep their own width.
+func planMultiLayout(meta *typeMeta, samples []reflect.Value, maxCol int) ([]multiLayout, int) {
+	reserved := reservedWriteColumns(meta)
+	next := maxCol + 1
+	nextFree := func() int {
+		for reserved[next] {
+			next++
+		}
+		next++
+		return next - 1
+	}
+
+	var out []multiLayout
+	for _, fm := range meta.Multi {
+		l := multiLayout{fm: fm}
+		width := 0
+		switch {
+		case fm.ColFrom >= 0:
+			width = fm.ColTo - fm.ColFrom + 1
+		case fm.NameTmpl == "" && fm.HeaderRe == nil && len(fm.ColumnNames) > 0:
+			width = len(fm.ColumnNames)
+		default:
+			for _, v := range samples {
+				if fv, err := v.FieldByIndexErr(fm.Index); err == nil {
+					width = max(width, fv.Len())
+				}
+			}
+		}
+		for i := 0; i < width; i++ {
+			col := fm.ColFrom + i
+			if fm.ColFrom < 0 {
+				col = nextFree()
+			}
+			l.cols = append(l.cols, col)
+			l.headers = append(l.headers, elementHeader(fm, i))
+		}
+		out = append(out, l)
+	}
+
+	if fm := meta.Rest; fm != nil {
+		keys := map[string]bool{}
+		for _, v := range samples {
+			if fv, err := v.FieldByIndexErr(fm.Index); err == nil {
+				for _, k := range fv.MapKeys() {
+					keys[k.String()] = true
+				}
+			}
+		}
+		l := multiLayout{fm: fm}
+		for k := range keys {
+			l.keys = append(l.keys, k)
+		}
+		sort.Strings(l.keys)
+		for _, k := range l.keys {
+			l.cols = append(l.cols, nextFree())
+			l.headers = append(l.headers, k)
+		}
+		out = append(out, l)
+	}
+
+	last := next - 1
+	for c := range reserved {
+		last = max(last, c)
+	}
+	return out, last
+}
+
+// writeMulti puts the slice elements and rest values of v into rowVals.
+func writeMulti(v reflect.Value, layouts []multiLayout, rowVals []any, cellOf func(reflect.Value, *fieldMeta) (any, error)) error {
+	for _, l := range layouts {
+		fv, err := v.FieldByIndexErr(l.fm.Index)
+		if err != nil {
+			continue
+		}
+		if l.fm.Rest {
+			used := 0
+			for i, k := range l.keys {
+				if e := fv.MapIndex(reflect.ValueOf(k).Convert(fv.Type().Key())); e.IsValid() {
+					cell, err := cellOf(e, l.fm)
+					if err != nil {
+						return fmt.Errorf("excelio: field %s[%q]: %w", l.fm.FieldName, k, err)
+					}
+					rowVals[l.cols[i]] = cell
+					used++
+				}
+			}
+			if used < fv.Len() {
+				return fmt.Errorf("excelio: field %s: key not in the column layout fixed by the first row", l.fm.FieldName)
+			}
+			continue
+		}
+		if fv.Len() > len(l.cols) {
+			return fmt.Errorf("excelio: field %s: %d values, but the column layout has %d", l.fm.FieldName, fv.Len(), len(l.cols))
+		}
+		for i := 0; i < fv.Len(); i++ {
+			cell, err := cellOf(fv.Index(i), l.fm)
+			if err != nil {
+				return fmt.Errorf("excelio: field %s[%d]: %w", l.fm.FieldName, i, err)
+			}
+			rowVals[l.cols[i]] = cell
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package excelio
+
+import (
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+/* =========================================================
+ *  Multi-row & merged headers
+ * ========================================================= */
+
+// headerPathSep joins the levels of a multi-row header: "Q1" over "Revenue"
+// becomes "Q1/Revenue".
+const headerPathSep = "/"
+
+// HeaderRows declares a header that spans rows start..end (1-based, inclusive),
+// such as a "Q1" group cell merged over "Revenue" and "Cost". Each column's
+// header is the path of its non-empty cells from top to bottom, joined by "/":
+//
+//	type Quarter struct {
+//	    Region    string  `excel:"Region"`      // merged vertically over both rows
+//	    Q1Revenue float64 `excel:"Q1/Revenue"`
+//	    Q1Cost    float64 `excel:"Q1/Cost"`
+//	}
+//
+// Merged header cells (XLSX) are expanded to every column and row they cover,
+// and repeated levels are collapsed, so a cell merged over both rows yields
+// "Region", not "Region/Region". Data starts at end+1 unless StartRow is set.
+func HeaderRows(start, end int) Option {
+	return func(o *Options) {
+		if end < start {
+			end = start
+		}
+		o.HeaderRow = start
+		o.HeaderRowEnd = end
+		if start > 0 && o.FirstDataRow == 0 {
+			o.FirstDataRow = end + 1
+		}
+	}
+}
+
+// headerEnd returns the last header row (HeaderRow for single-row headers).
+func (o *Options) headerEnd() int {
+	if o.HeaderRowEnd > o.HeaderRow {
+		return o.HeaderRowEnd
+	}
+	return o.HeaderRow
+}
+
+// mergeRange is a merged cell area: rows are 1-based, columns 0-based, inclusive.
+type mergeRange struct {
+	row1, col1, row2, col2 int
+	value                  string
+}
+
+// mergeBook is implemented by books that know about merged cells.
+type mergeBook interface {
+	merges(o *Options) ([]mergeRange, error)
+}
+
+// merges returns the merged areas of the sheet resolved by rows(o).
+func (b *xlsxBook) merges(o *Options) ([]mergeRange, error) {
+	sheet, err := resolveSheet(b.f, o)
+	if err != nil {
+		return nil, err
+	}
+	cells, err := b.f.GetMergeCells(sheet)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]mergeRange, 0, len(cells))
+	for _, mc := range cells {
+		c1, r1, err := excelize.CellNameToCoordinates(mc.GetStartAxis())
+		if err != nil {
+			return nil, err
+		}
+		c2, r2, err := excelize.CellNameToCoordinates(mc.GetEndAxis())
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, mergeRange{row1: r1, col1: c1 - 1, row2: r2, col2: c2 - 1, value: mc.GetCellValue()})
+	}
+	return out, nil
+}
+
+// composeHeader builds one header text per column from the header rows
+// (starting at sheet row first), after expanding merged cells.
+func composeHeader(rows [][]string, first int, merges []mergeRange) []string {
+	last := first + len(rows) - 1
+
+	width := 0
+	for _, r := range rows {
+		width = max(width, len(r))
+	}
+	for _, m := range merges {
+		if m.row2 >= first && m.row1 <= last {
+			width = max(width, m.col2+1)
+		}
+	}
+
+	grid := make([][]string, len(rows))
+	for i, r := range rows {
+		grid[i] = make([]string, width)
+		for c, v := range r {
+			grid[i][c] = strings.TrimSpace(v)
+		}
+	}
+
+	// Copy each merged value into every header cell the merge covers.
+	for _, m := range merges {
+		if m.row2 < first || m.row1 > last {
+			continue
+		}
+		v := strings.TrimSpace(m.value)
+		if m.row1 >= first {
+			v = grid[m.row1-first][m.col1]
+		}
+		for r := max(m.row1, first); r <= min(m.row2, last); r++ {
+			for c := m.col1; c <= m.col2; c++ {
+				grid[r-first][c] = v
+			}
+		}
+	}
+
+	out := make([]string, width)
+	parts := make([]string, 0, len(rows))
+	for c := 0; c < width; c++ {
+		parts = parts[:0]
+		for r := range grid {
+			v := grid[r][c]
+			if v == "" || (len(parts) > 0 && parts[len(parts)-1] == v) {
+				continue
+			}
+			parts = append(parts, v)
+		}
+		out[c] = strings.Join(parts, headerPathSep)
+	}
+	return out
+}
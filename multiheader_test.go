@@ -0,0 +1,88 @@
+package excelio
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestComposeHeader(t *testing.T) {
+	tests := []struct {
+		name   string
+		rows   [][]string
+		first  int
+		merges []mergeRange
+		want   []string
+	}{
+		{
+			name: "vertical and horizontal merges",
+			// Region merged over A2:A3, Q1 over B2:C2; excelize reports the
+			// value in the top-left cell only.
+			rows:   [][]string{{"Region", "Q1", "", "Note"}, {"", "Revenue", "Cost", ""}},
+			first:  2,
+			merges: []mergeRange{{row1: 2, col1: 0, row2: 3, col2: 0}, {row1: 2, col1: 1, row2: 2, col2: 2}},
+			want:   []string{"Region", "Q1/Revenue", "Q1/Cost", "Note"},
+		},
+		{
+			name:  "repeated levels collapse",
+			rows:  [][]string{{"Total", " Q1 "}, {"Total", "Q1"}, {"", "Sum"}},
+			first: 1,
+			want:  []string{"Total", "Q1/Sum"},
+		},
+		{
+			name:   "merge starting above the header uses its value",
+			rows:   [][]string{{"", "Code"}},
+			first:  2,
+			merges: []mergeRange{{row1: 1, col1: 0, row2: 2, col2: 0, value: " Group "}},
+			want:   []string{"Group", "Code"},
+		},
+		{
+			name:   "merges outside the header are ignored; merges widen it",
+			rows:   [][]string{{"A"}, {"B"}},
+			first:  1,
+			merges: []mergeRange{{row1: 5, col1: 0, row2: 6, col2: 4}, {row1: 1, col1: 0, row2: 1, col2: 1}},
+			want:   []string{"A/B", "A"},
+		},
+	}
+	for _, tt := range tests {
+		if got := composeHeader(tt.rows, tt.first, tt.merges); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: composeHeader = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+type quarterRow struct {
+	Region    string  `excel:"Region"`
+	Q1Revenue float64 `excel:"Q1/Revenue"`
+	Q1Cost    float64 `excel:"Q1/Cost"`
+}
+
+func TestHeaderRowsMergedXLSX(t *testing.T) {
+	f := excelize.NewFile()
+	sheet := f.GetSheetName(0)
+	_ = f.SetSheetRow(sheet, "A1", &[]any{"Sales report"})
+	_ = f.SetSheetRow(sheet, "A2", &[]any{"Region", "Q1"})
+	_ = f.SetSheetRow(sheet, "B3", &[]any{"Revenue", "Cost"})
+	_ = f.SetSheetRow(sheet, "A4", &[]any{"North", 100, 40})
+	if err := f.MergeCell(sheet, "A2", "A3"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.MergeCell(sheet, "B2", "C2"); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+
+	got, errs, err := Read[quarterRow](bytes.NewReader(buf.Bytes()), HeaderRows(2, 3), StrictHeaders())
+	if err != nil || len(errs) > 0 {
+		t.Fatalf("Read = %v, %v", errs, err)
+	}
+	if want := []quarterRow{{"North", 100, 40}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
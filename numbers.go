@@ -0,0 +1,233 @@
+package excelio
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/xuri/excelize/v2"
+)
+
+/* =========================================================
+ *  Locale-aware numbers & number formats
+ * ========================================================= */
+
+// Locale describes how numbers are written as text.
+type Locale struct {
+	Decimal rune // Decimal separator, e.g. '.' or ','
+	Group   rune // Digit grouping separator, e.g. ',', '.', ' ' or '\''
+}
+
+// Common number locales.
+var (
+	LocaleEN = Locale{Decimal: '.', Group: ','}  // 1,234.50 (also Thai, Japanese, ...)
+	LocaleDE = Locale{Decimal: ',', Group: '.'}  // 1.234,50 (also Spanish, Italian, Indonesian, ...)
+	LocaleFR = Locale{Decimal: ',', Group: ' '}  // 1 234,50 (also Russian, Polish, Nordic, ...)
+	LocaleCH = Locale{Decimal: '.', Group: '\''} // 1'234.50
+)
+
+// NumberLocale reads numbers written in loc, with grouping separators,
+// accounting negatives "(120)", percentages "12%" (read as 0.12) and currency
+// symbols or codes ("฿1,000", "1.000 €", "THB 1,000") for every numeric field.
+// Grouping must be regular (1,234,567 or Indian 12,34,567), so a number in
+// the wrong locale is an error rather than a different value.
+//
+// Numeric XLSX cells are formatted by the reader with '.' decimals; combine
+// with RawValues to read them as stored and apply the locale to text cells
+// only. CSV/TSV output writes decimals with loc's separator.
+func NumberLocale(loc Locale) Option {
+	return func(o *Options) { o.NumberLocale = &loc }
+}
+
+// localeNumbers reports whether numeric text of fm is parsed by parseLocaleNumber.
+func localeNumbers(fm *fieldMeta, o *Options) bool {
+	return (o != nil && o.NumberLocale != nil) || (fm != nil && fm.NumFmt != "")
+}
+
+// numberLocale returns the configured locale (LocaleEN by default).
+func numberLocale(o *Options) Locale {
+	if o != nil && o.NumberLocale != nil {
+		return *o.NumberLocale
+	}
+	return LocaleEN
+}
+
+// isNumberKind reports whether k is an integer or floating-point kind.
+func isNumberKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Float64 && k != reflect.Uintptr
+}
+
+// parseLocaleNumber converts a number written in loc into plain Go syntax
+// ("-1234.5"), applying parentheses negatives and percent scaling and
+// dropping currency symbols and codes.
+func parseLocaleNumber(s string, loc Locale) (string, error) {
+	orig := s
+	s = strings.TrimSpace(s)
+	neg := false
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		neg, s = true, s[1:len(s)-1]
+	}
+
+	// Currency symbols and percent signs may appear anywhere around the
+	// digits; currency codes and names only at either end.
+	pct := false
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r == '%':
+			pct = true
+			return -1
+		case unicode.Is(unicode.Sc, r):
+			return -1
+		}
+		return r
+	}, s)
+	s = strings.TrimFunc(s, func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.Is(unicode.Mn, r) || unicode.IsSpace(r)
+	})
+
+	switch {
+	case strings.HasPrefix(s, "-"):
+		neg, s = !neg, strings.TrimLeftFunc(s[1:], unicode.IsSpace)
+	case strings.HasPrefix(s, "+"):
+		s = strings.TrimLeftFunc(s[1:], unicode.IsSpace)
+	case strings.HasSuffix(s, "-"):
+		neg, s = !neg, strings.TrimRightFunc(s[:len(s)-1], unicode.IsSpace)
+	}
+
+	invalid := fmt.Errorf("invalid number %q", orig)
+	var groups []int
+	var intDigits, fracDigits strings.Builder
+	run, seenDecimal := 0, false
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			if seenDecimal {
+				fracDigits.WriteRune(r)
+			} else {
+				intDigits.WriteRune(r)
+				run++
+			}
+		case r == loc.Decimal && !seenDecimal:
+			seenDecimal = true
+		case !seenDecimal && isGroupRune(r, loc.Group):
+			if run == 0 {
+				return "", invalid
+			}
+			groups = append(groups, run)
+			run = 0
+		default:
+			return "", invalid
+		}
+	}
+	if intDigits.Len()+fracDigits.Len() == 0 {
+		return "", invalid
+	}
+	if len(groups) > 0 && !validGrouping(append(groups, run)) {
+		return "", invalid
+	}
+
+	digits, frac := intDigits.String(), fracDigits.String()
+	if pct {
+		digits = strings.Repeat("0", max(0, 3-len(digits))) + digits
+		digits, frac = digits[:len(digits)-2], digits[len(digits)-2:]+frac
+	}
+	digits = strings.TrimLeft(digits, "0")
+	if digits == "" {
+		digits = "0"
+	}
+	out := digits
+	if frac = strings.TrimRight(frac, "0"); frac != "" {
+		out += "." + frac
+	}
+	if neg && strings.Trim(out, "0.") != "" {
+		out = "-" + out
+	}
+	return out, nil
+}
+
+// validGrouping reports whether digit group lengths (most significant first)
+// follow thousands grouping (1,234,567) or Indian grouping (12,34,567).
+func validGrouping(groups []int) bool {
+	n := len(groups)
+	if groups[0] < 1 || groups[0] > 3 || groups[n-1] != 3 {
+		return false
+	}
+	middle := groups[1 : n-1]
+	for _, size := range []int{3, 2} {
+		ok := groups[0] <= size
+		for _, g := range middle {
+			ok = ok && g == size
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isGroupRune reports whether r separates digit groups for the group
+// separator g. Space grouping accepts any space (non-breaking, narrow, ...),
+// apostrophe grouping the typographic apostrophe.
+func isGroupRune(r, g rune) bool {
+	switch {
+	case r == g:
+		return true
+	case g == ' ':
+		return unicode.IsSpace(r)
+	case g == '\'':
+		return r == '\u2019'
+	}
+	return false
+}
+
+// styleSink is implemented by sinks that can style cells (XLSX).
+type styleSink interface {
+	// numFmtStyle returns a style ID applying the Excel number format code.
+	numFmtStyle(code string) (int, error)
+}
+
+// newNumFmtStyle creates (or reuses) a style of f with the number format code.
+func newNumFmtStyle(f *excelize.File, code string) (int, error) {
+	return f.NewStyle(&excelize.Style{CustomNumFmt: &code})
+}
+
+// cell converts a field value for the sink and applies the Write functions of
+// its transforms. On sinks with styles, fields with a `numfmt` tag get that
+// number format, and their times are written as native Excel dates instead of text.
+func (sw *StreamWriter[T]) cell(fv reflect.Value, fm *fieldMeta) (any, error) {
+	v, err := valueToCell(fv, fm, sw.opts)
+	if err != nil {
+		return nil, err
+	}
+	v = writeTransform(v, fm, sw.opts)
+	if fm.NumFmt == "" {
+		return v, nil
+	}
+	ss, ok := sw.sink.(styleSink)
+	if !ok {
+		return v, nil
+	}
+	tv := reflect.Indirect(fv)
+	if tv.IsValid() && isSQLNull(tv.Type()) {
+		tv, _ = sqlNullValue(tv)
+	}
+	if tv.IsValid() && tv.Type() == timeType {
+		if t := tv.Interface().(time.Time); !t.IsZero() {
+			v = t.In(timeLocation(sw.opts))
+		}
+	}
+
+	style, ok := sw.numFmtStyles[fm.NumFmt]
+	if !ok {
+		if style, err = ss.numFmtStyle(fm.NumFmt); err != nil {
+			return nil, err
+		}
+		if sw.numFmtStyles == nil {
+			sw.numFmtStyles = make(map[string]int)
+		}
+		sw.numFmtStyles[fm.NumFmt] = style
+	}
+	return excelize.Cell{StyleID: style, Value: v}, nil
+}
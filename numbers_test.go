@@ -0,0 +1,105 @@
+package excelio
+
+import "testing"
+
+func TestParseLocaleNumber(t *testing.T) {
+	tests := []struct {
+		in   string
+		loc  Locale
+		want string // "" = invalid
+	}{
+		{"1234", LocaleEN, "1234"},
+		{"1,234.50", LocaleEN, "1234.5"},
+		{" +1,234,567.125 ", LocaleEN, "1234567.125"},
+		{"0.00", LocaleEN, "0"},
+		{".5", LocaleEN, "0.5"},
+		{"007", LocaleEN, "7"},
+
+		// Negatives.
+		{"-1,234", LocaleEN, "-1234"},
+		{"(120)", LocaleEN, "-120"},
+		{"(1,234.50)", LocaleEN, "-1234.5"},
+		{"1000-", LocaleEN, "-1000"},
+		{"(-5)", LocaleEN, "5"},
+		{"-0", LocaleEN, "0"},
+
+		// Percentages.
+		{"12%", LocaleEN, "0.12"},
+		{"12.5%", LocaleEN, "0.125"},
+		{"5%", LocaleEN, "0.05"},
+		{"0.5 %", LocaleEN, "0.005"},
+		{"(3%)", LocaleEN, "-0.03"},
+		{"1,250%", LocaleEN, "12.5"},
+
+		// Currency symbols, codes and names.
+		{"฿1,000", LocaleEN, "1000"},
+		{"$ 12.30", LocaleEN, "12.3"},
+		{"THB 1,000", LocaleEN, "1000"},
+		{"1,000 บาท", LocaleEN, "1000"},
+		{"-€5", LocaleEN, "-5"},
+		{"1.000 €", LocaleDE, "1000"},
+
+		// Indian grouping.
+		{"12,34,567", LocaleEN, "1234567"},
+		{"1,23,45,678.9", LocaleEN, "12345678.9"},
+
+		// Other locales.
+		{"1.234,50", LocaleDE, "1234.5"},
+		{"-1.234.567,89", LocaleDE, "-1234567.89"},
+		{"1 234,50", LocaleFR, "1234.5"},
+		{"1 234 567,5", LocaleFR, "1234567.5"},
+		{"1'234.50", LocaleCH, "1234.5"},
+		{"1’234.50", LocaleCH, "1234.5"},
+
+		// Invalid: wrong locale, irregular grouping, no digits.
+		{"1.234,50", LocaleEN, ""},
+		{"1,234.50", LocaleDE, ""},
+		{"1 234.50", LocaleEN, ""},
+		{"1,23", LocaleEN, ""},
+		{"12,3456", LocaleEN, ""},
+		{"1234,567", LocaleEN, ""},
+		{"123,45,678", LocaleEN, ""},
+		{",123", LocaleEN, ""},
+		{"1,,234", LocaleEN, ""},
+		{"1.2.3", LocaleEN, ""},
+		{"(-)", LocaleEN, ""},
+		{"%", LocaleEN, ""},
+		{"", LocaleEN, ""},
+		{"abc", LocaleEN, ""},
+		{"12a34", LocaleEN, ""},
+	}
+	for _, tt := range tests {
+		got, err := parseLocaleNumber(tt.in, tt.loc)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("parseLocaleNumber(%q, %q) = %q, want error", tt.in, tt.loc.Decimal, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("parseLocaleNumber(%q, %q) = %q, %v; want %q", tt.in, tt.loc.Decimal, got, err, tt.want)
+		}
+	}
+}
+
+func TestValidGrouping(t *testing.T) {
+	tests := []struct {
+		groups []int
+		want   bool
+	}{
+		{[]int{1, 3}, true},
+		{[]int{3, 3, 3}, true},
+		{[]int{2, 2, 3}, true},
+		{[]int{1, 2, 2, 3}, true},
+		{[]int{4, 3}, false},
+		{[]int{1, 2}, false},
+		{[]int{3, 2, 3}, false},
+		{[]int{2, 3, 2, 3}, false},
+		{[]int{1, 4}, false},
+	}
+	for _, tt := range tests {
+		if got := validGrouping(tt.groups); got != tt.want {
+			t.Errorf("validGrouping(%v) = %v, want %v", tt.groups, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package excelio
+
+import (
+	"errors"
+	"sync"
+)
+
+/* =========================================================
+ *  Parallel row mapping
+ * ========================================================= */
+
+// Workers maps and validates rows on n goroutines while a single goroutine
+// keeps decoding the sheet. Handlers (OnStreamRow, progress) still run on the
+// calling goroutine, one row at a time, in sheet order unless Unordered() is set.
+//
+// This pays off when mapping dominates (many columns, custom converters,
+// expensive validation); for small or simple sheets the default is faster.
+// Custom converters and validators must be safe for concurrent use.
+func Workers(n int) Option {
+	return func(o *Options) { o.Workers = n }
+}
+
+// Unordered delivers rows to the handler as soon as a worker has mapped them,
+// instead of restoring sheet order. Only meaningful together with Workers(n).
+// With Read/ReadFile the returned slice is then not in sheet order either.
+func Unordered() Option {
+	return func(o *Options) { o.Unordered = true }
+}
+
+// errScanStopped signals the producer that the consumer has stopped.
+var errScanStopped = errors.New("excelio: scan stopped")
+
+// scanParallel runs the decode → map → deliver pipeline:
+//
+//	produce (1 goroutine) → jobs → mapOne (o.Workers goroutines) → results → fn (caller)
+//
+// At most 4×Workers rows are in flight, which bounds memory in ordered mode
+// when a single slow row holds back delivery.
+func scanParallel[T any](sc *sheetScanner[T], rows rowIterator, fn rowFunc[T]) error {
+	n := sc.o.Workers
+	maxInFlight := 4 * n
+
+	jobs := make(chan rawRow, n)
+	results := make(chan mappedRow[T], n)
+	tokens := make(chan struct{}, maxInFlight)
+	stop := make(chan struct{})
+
+	var produceErr error
+	var producerWG, workerWG sync.WaitGroup
+
+	// Producer: decodes rows in sheet order.
+	producerWG.Add(1)
+	go func() {
+		defer producerWG.Done()
+		defer close(jobs)
+		produceErr = sc.produce(rows, func(rr rawRow) error {
+			select {
+			case tokens <- struct{}{}:
+			case <-stop:
+				return errScanStopped
+			}
+			select {
+			case jobs <- rr:
+				return nil
+			case <-stop:
+				return errScanStopped
+			}
+		})
+	}()
+
+	// Workers: map and validate.
+	workerWG.Add(n)
+	for i := 0; i < n; i++ {
+		go func() {
+			defer workerWG.Done()
+			for rr := range jobs {
+				m := sc.mapOne(rr)
+				select {
+				case results <- m:
+				case <-stop:
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		workerWG.Wait()
+		close(results)
+	}()
+
+	// Consumer: deliver to fn on the calling goroutine.
+	var fnErr error
+	next := 0
+	pending := make(map[int]mappedRow[T])
+	deliver := func(m mappedRow[T]) {
+		<-tokens
+		if fnErr == nil {
+			// Rows mapped before a cancellation must not reach fn.
+			if fnErr = ctxErr(sc.o.ctx); fnErr == nil {
+				fnErr = fn(m.rowIdx, m.logicalIdx, m.obj, m.rowErrs, m.ok)
+			}
+			if fnErr != nil {
+				close(stop)
+			}
+		}
+	}
+
+	for m := range results {
+		if fnErr != nil {
+			continue // drain
+		}
+		if sc.o.Unordered {
+			deliver(m)
+			continue
+		}
+		pending[m.seq] = m
+		for {
+			pm, ok := pending[next]
+			if !ok || fnErr != nil {
+				break
+			}
+			delete(pending, next)
+			next++
+			deliver(pm)
+		}
+	}
+	producerWG.Wait()
+
+	if fnErr != nil {
+		return fnErr
+	}
+	if produceErr != nil && produceErr != errScanStopped {
+		return produceErr
+	}
+	return nil
+}
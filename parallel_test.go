@@ -0,0 +1,89 @@
+package excelio
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+func TestParallelStopsDeliveryOnCancel(t *testing.T) {
+	data := concFixture(t, FormatCSV)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	delivered := 0
+	_, err := StreamContext[concItem](ctx, bytes.NewReader(data), Format(FormatCSV), Workers(3),
+		OnStreamRow(func(rowIdx, logicalIdx int, obj *concItem, rowErrs []RowError) error {
+			delivered++
+			if delivered == 10 {
+				cancel()
+			}
+			return nil
+		}))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if delivered != 10 {
+		t.Errorf("delivered %d rows, want 10", delivered)
+	}
+}
+
+type benchItem struct {
+	Code    string    `excel:"Code" validate:"required"`
+	Name    string    `excel:"Name" transform:"trim"`
+	Qty     int       `excel:"Qty" validate:"gte=0"`
+	Price   float64   `excel:"Price" numfmt:"#,##0.00"`
+	Ordered time.Time `excel:"Ordered" fmt:"2006-01-02"`
+	Email   string    `excel:"Email" validate:"email"`
+}
+
+// benchFixture builds an XLSX sheet of n rows of benchItem.
+func benchFixture(b *testing.B, n int) []byte {
+	b.Helper()
+	rows := make([]benchItem, n)
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range rows {
+		rows[i] = benchItem{
+			Code:    fmt.Sprintf("P%06d", i),
+			Name:    fmt.Sprintf("  Product %d  ", i),
+			Qty:     i % 100,
+			Price:   float64(i) * 1.25,
+			Ordered: day.AddDate(0, 0, i%365),
+			Email:   fmt.Sprintf("buyer%d@example.com", i),
+		}
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, rows); err != nil {
+		b.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func benchmarkRead(b *testing.B, workers int) {
+	data := benchFixture(b, 5000)
+	v := validator.New()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		items, errs, err := Read[benchItem](bytes.NewReader(data), UseValidator(v), Workers(workers))
+		if err != nil || len(errs) > 0 || len(items) != 5000 {
+			b.Fatalf("Read = %d rows, %v, %v", len(items), errs, err)
+		}
+	}
+}
+
+// BenchmarkRead maps every row on the reading goroutine.
+func BenchmarkRead(b *testing.B) { benchmarkRead(b, 0) }
+
+// BenchmarkReadWorkers maps rows on n goroutines; compare with BenchmarkRead
+// on a machine with at least n CPUs.
+func BenchmarkReadWorkers(b *testing.B) {
+	for _, n := range []int{2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", n), func(b *testing.B) { benchmarkRead(b, n) })
+	}
+}
@@ -0,0 +1,134 @@
+package excelio
+
+import (
+	"fmt"
+	"io"
+)
+
+/* =========================================================
+ *  Multi-sheet typed reader
+ * ========================================================= */
+
+// SheetSpec describes one sheet to map with ReadSheets / ReadSheetsFile.
+// Create it with SheetInto.
+type SheetSpec struct {
+	read      func(b book) (SheetResult, error)
+	rawValues bool // the sheet is read with RawValues()
+}
+
+// SheetResult is the per-sheet outcome of ReadSheets / ReadSheetsFile.
+type SheetResult struct {
+	Sheet     string     // Resolved sheet name
+	HeaderRow int        // Header row used (the detected one with AutoHeader)
+	Rows      int        // Number of valid rows mapped
+	Errors    []RowError // Errors for rows of this sheet
+}
+
+// SheetInto maps the sheet selected by Sheet(...) / SheetAt(...) into dst,
+// using its own options (Header, StartRow, UseValidator, converters, ...).
+// Valid rows are appended to dst. If OnStreamRow(...) is also given, every row
+// is passed to the handler as in Stream (OnStreamBatch works the same way);
+// dst may then be nil.
+func SheetInto[T any](dst *[]T, opts ...Option) SheetSpec {
+	return SheetSpec{rawValues: buildOptions(opts).RawCellValues, read: func(b book) (SheetResult, error) {
+		o := buildOptions(opts)
+
+		if dst == nil && o.streamHandler == nil {
+			return SheetResult{Sheet: o.SheetName}, fmt.Errorf("excelio: SheetInto needs a destination slice, OnStreamRow() or OnStreamBatch()")
+		}
+
+		var res SheetResult
+		lastRow, sorted := 0, true
+		o.firstDuplicates = o.streamHandler == nil // rows are only buffered in dst
+		err := scanSheet(b, o, func(rowIdx, logicalIdx int, obj T, rowErrs []RowError, ok bool) error {
+			if len(rowErrs) > 0 {
+				res.Errors = append(res.Errors, rowErrs...)
+			}
+			if ok {
+				res.Rows++
+				if dst != nil {
+					*dst = append(*dst, obj)
+				}
+			}
+			if rowIdx > 0 && rowIdx < lastRow {
+				sorted = false // first-row duplicate errors arrive after the last row
+			}
+			lastRow = max(lastRow, rowIdx)
+			if o.streamHandler == nil {
+				return nil
+			}
+			var objAny any
+			if ok {
+				objCopy := obj
+				objAny = &objCopy
+			}
+			return o.streamHandler(rowIdx, logicalIdx, objAny, rowErrs)
+		})
+		if err == nil && o.streamFlush != nil {
+			err = o.streamFlush()
+		}
+		if !sorted && !o.Unordered {
+			sortByRow(res.Errors)
+		}
+
+		res.Sheet = o.sheetResolved
+		res.HeaderRow = o.HeaderRow
+		if res.Sheet == "" {
+			res.Sheet = o.SheetName
+		}
+		return res, err
+	}}
+}
+
+// readSheets runs every spec against the opened book, in order.
+// On a fatal error the results gathered so far are returned with the error.
+func readSheets(b book, specs []SheetSpec) ([]SheetResult, error) {
+	results := make([]SheetResult, 0, len(specs))
+	for _, spec := range specs {
+		if spec.read == nil {
+			return results, fmt.Errorf("excelio: empty SheetSpec; use SheetInto")
+		}
+		res, err := spec.read(b)
+		results = append(results, res)
+		if err != nil {
+			return results, fmt.Errorf("excelio: sheet %q: %w", res.Sheet, err)
+		}
+	}
+	return results, nil
+}
+
+// ReadSheetsFile opens the workbook at path once and maps several sheets,
+// each into its own struct type:
+//
+//	var orders []Order
+//	var lines []OrderLine
+//	results, err := excelio.ReadSheetsFile("upload.xlsx",
+//	    excelio.SheetInto(&orders, excelio.Sheet("Orders"), excelio.UseValidator(v)),
+//	    excelio.SheetInto(&lines, excelio.Sheet("Lines"), excelio.Header(2)),
+//	)
+//
+// It returns one SheetResult per spec, in the same order.
+func ReadSheetsFile(path string, specs ...SheetSpec) ([]SheetResult, error) {
+	b, err := openBookFile(path, &Options{})
+	if err != nil {
+		return nil, err
+	}
+	defer b.Close()
+
+	return readSheets(b, specs)
+}
+
+// ReadSheets is like ReadSheetsFile but reads the workbook from an io.Reader.
+func ReadSheets(r io.Reader, specs ...SheetSpec) ([]SheetResult, error) {
+	o := &Options{}
+	for _, spec := range specs {
+		o.RawCellValues = o.RawCellValues || spec.rawValues
+	}
+	b, err := openBookReader(r, o)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Close()
+
+	return readSheets(b, specs)
+}
@@ -0,0 +1,246 @@
+package excelio
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+/* =========================================================
+ *  File Formats
+ * ========================================================= */
+
+// FileFormat identifies the container format of a sheet source or target.
+type FileFormat int
+
+const (
+	// FormatAuto detects the format from the file extension or, for readers,
+	// from the leading magic bytes. Writers without a path default to XLSX.
+	FormatAuto FileFormat = iota
+	// FormatXLSX is an Office Open XML workbook (.xlsx, .xlsm, ...).
+	FormatXLSX
+	// FormatCSV is comma-separated text (delimiter configurable via Delimiter).
+	FormatCSV
+	// FormatTSV is tab-separated text.
+	FormatTSV
+)
+
+// String returns the lowercase format name.
+func (f FileFormat) String() string {
+	switch f {
+	case FormatXLSX:
+		return "xlsx"
+	case FormatCSV:
+		return "csv"
+	case FormatTSV:
+		return "tsv"
+	}
+	return "auto"
+}
+
+// isText reports whether the format is a delimited text format.
+func (f FileFormat) isText() bool {
+	return f == FormatCSV || f == FormatTSV
+}
+
+var (
+	zipMagic = []byte("PK\x03\x04")
+	oleMagic = []byte{0xD0, 0xCF, 0x11, 0xE0} // encrypted OOXML container
+)
+
+// formatFromExt maps a file extension to a FileFormat (FormatAuto if unknown).
+func formatFromExt(path string) FileFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".xlsx", ".xlsm", ".xltx", ".xltm":
+		return FormatXLSX
+	case ".csv":
+		return FormatCSV
+	case ".tsv", ".tab":
+		return FormatTSV
+	}
+	return FormatAuto
+}
+
+// formatFromMagic detects XLSX by its ZIP/OLE signature; anything else is
+// treated as delimited text (CSV, refined later by delimiter sniffing).
+func formatFromMagic(head []byte) FileFormat {
+	if bytes.HasPrefix(head, zipMagic) || bytes.HasPrefix(head, oleMagic) {
+		return FormatXLSX
+	}
+	return FormatCSV
+}
+
+/* =========================================================
+ *  Row Sources
+ * ========================================================= */
+
+// rowIterator iterates over the rows of a single sheet. Every physical row is
+// returned, including empty ones, so row numbers match the source.
+type rowIterator interface {
+	Next() bool
+	Columns() ([]string, error)
+	// Err returns the error that ended the iteration early, if any.
+	Err() error
+	Close() error
+}
+
+// book is an opened sheet source: an XLSX workbook or a delimited text file.
+type book interface {
+	// rows resolves the sheet from Options and opens an iterator over it.
+	rows(o *Options) (rowIterator, error)
+	Close() error
+}
+
+// xlsxBook is a book backed by an excelize workbook.
+type xlsxBook struct {
+	f    *excelize.File
+	path string // Source file, or
+	data []byte // source bytes; re-read for cell types (see celltypes.go)
+}
+
+// xlsxRows adapts *excelize.Rows to rowIterator. With RawValues it also
+// reports the cell types of each row (kindedRows).
+type xlsxRows struct {
+	*excelize.Rows
+	opts  excelize.Options // RawCellValue for RawValues()
+	types *sheetCellKinds  // nil unless RawValues
+	row   int              // current row number (1-based)
+	kinds []cellKind
+}
+
+func (r *xlsxRows) Next() bool {
+	r.row++
+	return r.Rows.Next()
+}
+
+func (r *xlsxRows) Columns() ([]string, error) {
+	cols, err := r.Rows.Columns(r.opts)
+	if err != nil || r.types == nil {
+		return cols, err
+	}
+	r.kinds, err = r.types.at(r.row)
+	return cols, err
+}
+
+func (r *xlsxRows) Err() error { return r.Rows.Error() }
+
+func (r *xlsxRows) cellKinds() []cellKind { return r.kinds }
+
+func (r *xlsxRows) Close() error {
+	err := r.Rows.Close()
+	if r.types != nil {
+		if cerr := r.types.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+func (b *xlsxBook) rows(o *Options) (rowIterator, error) {
+	sheet, err := resolveSheet(b.f, o)
+	if err != nil {
+		return nil, err
+	}
+	props, err := b.f.GetWorkbookProps()
+	if err != nil {
+		return nil, err
+	}
+	if props.Date1904 != nil && *props.Date1904 {
+		o.Date1904 = true
+	}
+	rows, err := b.f.Rows(sheet)
+	if err != nil {
+		return nil, err
+	}
+	xr := &xlsxRows{Rows: rows, opts: excelize.Options{RawCellValue: o.RawCellValues}}
+	if o.RawCellValues && (b.data != nil || b.path != "") {
+		if xr.types, err = openCellKinds(b, sheet); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+	}
+	return xr, nil
+}
+
+func (b *xlsxBook) Close() error { return b.f.Close() }
+
+// setDate1904 marks the workbook f as using the 1904 date system.
+func setDate1904(f *excelize.File) error {
+	date1904 := true
+	return f.SetWorkbookProps(&excelize.WorkbookPropsOptions{Date1904: &date1904})
+}
+
+// openBookFile opens the file at path using the format from Options,
+// the file extension, or its content (in that order).
+func openBookFile(path string, o *Options) (book, error) {
+	format := o.Format
+	if format == FormatAuto {
+		format = formatFromExt(path)
+	}
+	if format == FormatXLSX {
+		f, err := excelize.OpenFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return &xlsxBook{f: f, path: path}, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	br := bufio.NewReader(file)
+	if format == FormatAuto {
+		head, _ := br.Peek(len(zipMagic))
+		if formatFromMagic(head) == FormatXLSX {
+			// Reopen through excelize so the workbook keeps its path for Save().
+			_ = file.Close()
+			f, err := excelize.OpenFile(path)
+			if err != nil {
+				return nil, err
+			}
+			return &xlsxBook{f: f, path: path}, nil
+		}
+	}
+	return newCSVBook(br, file, format, o), nil
+}
+
+// openBookReader opens a sheet source from r using the format from Options
+// or, if FormatAuto, the leading magic bytes.
+func openBookReader(r io.Reader, o *Options) (book, error) {
+	if r == nil {
+		return nil, fmt.Errorf("excelio: reader must not be nil")
+	}
+	format := o.Format
+	br := bufio.NewReader(r)
+	if format == FormatAuto {
+		head, _ := br.Peek(len(zipMagic))
+		format = formatFromMagic(head)
+	}
+	if format == FormatXLSX {
+		if !o.RawCellValues {
+			f, err := excelize.OpenReader(br)
+			if err != nil {
+				return nil, err
+			}
+			return &xlsxBook{f: f}, nil
+		}
+		// RawValues re-reads the worksheet XML for cell types, so keep the bytes.
+		data, err := io.ReadAll(br)
+		if err != nil {
+			return nil, err
+		}
+		f, err := excelize.OpenReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		return &xlsxBook{f: f, data: data}, nil
+	}
+	return newCSVBook(br, nil, format, o), nil
+}
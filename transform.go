@@ -0,0 +1,121 @@
+package excelio
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+/* =========================================================
+ *  Value transforms
+ * ========================================================= */
+
+// Transform rewrites cell text for fields tagged `transform:"name"`.
+// Read runs on the raw cell before the empty check and conversion; Write, if
+// set, runs on the text of string cells when writing, e.g. to format a phone
+// number that Read reduced to digits. Either may be nil.
+type Transform struct {
+	Read  func(string) string
+	Write func(string) string
+}
+
+// builtinTransforms are available to every `transform` tag.
+var builtinTransforms = map[string]Transform{
+	"trim":     {Read: strings.TrimSpace},
+	"upper":    {Read: strings.ToUpper},
+	"lower":    {Read: strings.ToLower},
+	"nospace":  {Read: removeRunes(unicode.IsSpace)},
+	"collapse": {Read: func(s string) string { return strings.Join(strings.Fields(s), " ") }},
+	"noapos":   {Read: func(s string) string { return strings.TrimPrefix(s, "'") }},
+	"nozw":     {Read: removeRunes(func(r rune) bool { return unicode.Is(unicode.Cf, r) })},
+	"digits":   {Read: removeRunes(func(r rune) bool { return r < '0' || r > '9' })},
+}
+
+var globalTransforms sync.Map // map[string]Transform
+
+// RegisterTransform makes a named transform available to `transform` tags of
+// all reads and writes; it replaces a built-in or earlier one with the same name.
+// Call it during initialization.
+func RegisterTransform(name string, t Transform) {
+	globalTransforms.Store(name, t)
+}
+
+// WithTransform registers a named transform for a single Read/Stream/Write
+// call. It takes precedence over RegisterTransform and the built-ins.
+func WithTransform(name string, t Transform) Option {
+	return func(o *Options) {
+		if o.transforms == nil {
+			o.transforms = make(map[string]Transform)
+		}
+		o.transforms[name] = t
+	}
+}
+
+// lookupTransform returns the transform called name, checking Options, the
+// global registry and the built-ins in that order.
+func lookupTransform(name string, o *Options) (Transform, bool) {
+	if o != nil {
+		if t, ok := o.transforms[name]; ok {
+			return t, true
+		}
+	}
+	if t, ok := globalTransforms.Load(name); ok {
+		return t.(Transform), true
+	}
+	t, ok := builtinTransforms[name]
+	return t, ok
+}
+
+// checkTransforms reports the first `transform` name of meta that is not registered.
+func checkTransforms(meta *typeMeta, o *Options) error {
+	fields := append(append([]*fieldMeta{}, meta.Fields...), meta.Multi...)
+	if meta.Rest != nil {
+		fields = append(fields, meta.Rest)
+	}
+	for _, fm := range fields {
+		for _, name := range fm.Transforms {
+			if _, ok := lookupTransform(name, o); !ok {
+				return fmt.Errorf("excelio: field %s: unknown transform %q", fm.FieldName, name)
+			}
+		}
+	}
+	return nil
+}
+
+// readTransform applies the Read functions of fm's transforms, left to right.
+func readTransform(raw string, fm *fieldMeta, o *Options) string {
+	for _, name := range fm.Transforms {
+		if t, _ := lookupTransform(name, o); t.Read != nil {
+			raw = t.Read(raw)
+		}
+	}
+	return raw
+}
+
+// writeTransform applies the Write functions of fm's transforms to a string
+// cell value, right to left.
+func writeTransform(cell any, fm *fieldMeta, o *Options) any {
+	s, ok := cell.(string)
+	if !ok || fm == nil {
+		return cell
+	}
+	for i := len(fm.Transforms) - 1; i >= 0; i-- {
+		if t, _ := lookupTransform(fm.Transforms[i], o); t.Write != nil {
+			s = t.Write(s)
+		}
+	}
+	return s
+}
+
+// removeRunes returns a transform deleting the runes matching drop.
+func removeRunes(drop func(rune) bool) func(string) string {
+	return func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if drop(r) {
+				return -1
+			}
+			return r
+		}, s)
+	}
+}
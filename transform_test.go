@@ -0,0 +1,115 @@
+package excelio
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestBuiltinTransforms(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"trim", "  a b \t", "a b"},
+		{"upper", "abc é", "ABC É"},
+		{"lower", "ABC", "abc"},
+		{"nospace", " 08 1234 5678 ", "0812345678"},
+		{"collapse", "  a   b \n c ", "a b c"},
+		{"noapos", "'00123", "00123"},
+		{"noapos", "it's", "it's"},
+		{"nozw", "ab\u200bc\ufeff", "abc"},
+		{"digits", "+66 (81) 234-5678", "66812345678"},
+	}
+	for _, tt := range tests {
+		if got := builtinTransforms[tt.name].Read(tt.in); got != tt.want {
+			t.Errorf("%s(%q) = %q, want %q", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
+
+type transformRow struct {
+	Phone string `excel:"Phone" transform:"trim,digits,tr_phone"`
+	Code  string `excel:"Code" transform:"tr_tag,trim"`
+}
+
+func init() {
+	RegisterTransform("tr_phone", Transform{
+		Write: func(s string) string {
+			if len(s) != 10 {
+				return s
+			}
+			return s[:3] + "-" + s[3:6] + "-" + s[6:]
+		},
+	})
+	RegisterTransform("tr_tag", Transform{Read: func(s string) string { return "G" + s }})
+}
+
+func TestTransformRead(t *testing.T) {
+	src := "Phone,Code\n\" 081-234-5678 \", x \n"
+
+	// Read transforms run left to right: "tr_tag" before "trim".
+	items, errs, err := Read[transformRow](strings.NewReader(src), Format(FormatCSV))
+	if err != nil || len(errs) > 0 || len(items) != 1 {
+		t.Fatalf("Read = %v, %v, %v", items, errs, err)
+	}
+	if items[0] != (transformRow{Phone: "0812345678", Code: "G x"}) {
+		t.Errorf("got %+v", items[0])
+	}
+
+	// WithTransform wins over RegisterTransform, which wins over a built-in.
+	RegisterTransform("trim", Transform{Read: func(s string) string { return "R" + strings.TrimSpace(s) }})
+	t.Cleanup(func() { globalTransforms.Delete("trim") })
+	items, _, err = Read[transformRow](strings.NewReader(src), Format(FormatCSV),
+		WithTransform("tr_tag", Transform{Read: func(s string) string { return "W" + s }}))
+	if err != nil || len(items) != 1 || items[0].Code != "RW x" || items[0].Phone != "0812345678" {
+		t.Errorf("precedence: got %+v, %v", items, err)
+	}
+}
+
+type unknownTransformRow struct {
+	Code string `excel:"Code" transform:"trim,tr_missing"`
+}
+
+func TestTransformUnknown(t *testing.T) {
+	want := `excelio: field Code: unknown transform "tr_missing"`
+	_, _, err := Read[unknownTransformRow](strings.NewReader("Code\nx\n"), Format(FormatCSV))
+	if err == nil || err.Error() != want {
+		t.Errorf("Read err = %v, want %q", err, want)
+	}
+	err = Write(&bytes.Buffer{}, []unknownTransformRow{{Code: "x"}}, Format(FormatCSV))
+	if err == nil || err.Error() != want {
+		t.Errorf("Write err = %v, want %q", err, want)
+	}
+	// Registering it for the call is enough.
+	_, _, err = Read[unknownTransformRow](strings.NewReader("Code\nx\n"), Format(FormatCSV),
+		WithTransform("tr_missing", Transform{}))
+	if err != nil {
+		t.Errorf("with WithTransform: %v", err)
+	}
+}
+
+type writeTransformRow struct {
+	Code string `excel:"Code" transform:"tr_a,tr_b"`
+}
+
+func TestTransformWrite(t *testing.T) {
+	// Write inverses run right to left: tr_b first, then tr_a.
+	var buf bytes.Buffer
+	err := Write(&buf, []writeTransformRow{{Code: "x"}}, Format(FormatCSV),
+		WithTransform("tr_a", Transform{Write: func(s string) string { return s + "a" }}),
+		WithTransform("tr_b", Transform{Write: func(s string) string { return s + "b" }}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Code\r\nxba\r\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := Write(&buf, []transformRow{{Phone: "0812345678", Code: "x"}}, Format(FormatCSV)); err != nil {
+		t.Fatal(err)
+	}
+	if want := "Phone,Code\r\n081-234-5678,x\r\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,257 @@
+package excelio
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/xuri/excelize/v2"
+)
+
+/* =========================================================
+ *  Workbook: multi-sheet writer
+ * ========================================================= */
+
+// Workbook writes several typed sheets into a single XLSX file.
+// Each sheet is a StreamWriter[T] created with AddSheet, so different sheets
+// can hold different struct types. Everything is flushed and written by a
+// single call to Close.
+//
+// Usage:
+//
+//	wb, _ := excelio.NewWorkbookFile("report.xlsx")
+//	orders, _ := excelio.AddSheet[Order](wb, excelio.Sheet("Orders"))
+//	lines, _ := excelio.AddSheet[OrderLine](wb, excelio.Sheet("Lines"))
+//	_ = orders.WriteRows(allOrders)
+//	_ = lines.WriteRows(allLines)
+//	_ = wb.SetActiveSheet("Orders")
+//	if err := wb.Close(); err != nil { ... }
+type Workbook struct {
+	f      *excelize.File
+	out    io.Writer
+	path   string
+	sheets []*workbookSheet
+
+	defaultSheet string   // initial "Sheet1", removed once a named sheet is added
+	order        []string // optional sheet order applied on Close
+	active       string   // optional active sheet applied on Close
+	closed       bool
+}
+
+// workbookSheet is the rowSink of a single Workbook sheet. Closing it only
+// flushes the sheet; the file itself is written by Workbook.Close.
+type workbookSheet struct {
+	name    string
+	f       *excelize.File
+	sw      *excelize.StreamWriter
+	flushed bool
+
+	// finish closes the StreamWriter of the sheet, so that a header still
+	// waiting for the first row (slice and rest fields) is written too.
+	finish func() error
+}
+
+func (s *workbookSheet) numFmtStyle(code string) (int, error) { return newNumFmtStyle(s.f, code) }
+
+func (s *workbookSheet) addDropdown(col, fromRow int, labels []string) error {
+	return addDropdown(s.f, s.name, col, fromRow, labels)
+}
+
+func (s *workbookSheet) setRow(row int, vals []any) error {
+	if s.flushed {
+		return fmt.Errorf("excelio: sheet %q is already closed", s.name)
+	}
+	return s.sw.SetRow(fmt.Sprintf("A%d", row), vals)
+}
+
+func (s *workbookSheet) close() error {
+	if s.flushed {
+		return nil
+	}
+	s.flushed = true
+	return s.sw.Flush()
+}
+
+// newWorkbook creates an empty Workbook writing to out or path.
+func newWorkbook(out io.Writer, path string) *Workbook {
+	f := excelize.NewFile()
+	return &Workbook{
+		f:            f,
+		out:          out,
+		path:         path,
+		defaultSheet: f.GetSheetName(f.GetActiveSheetIndex()),
+	}
+}
+
+// NewWorkbookFile creates a multi-sheet writer that saves to a file path on Close.
+func NewWorkbookFile(path string) (*Workbook, error) {
+	if path == "" {
+		return nil, fmt.Errorf("excelio: path must not be empty")
+	}
+	return newWorkbook(nil, path), nil
+}
+
+// NewWorkbook creates a multi-sheet writer that writes to an io.Writer on Close,
+// such as an HTTP response or bytes.Buffer.
+func NewWorkbook(w io.Writer) (*Workbook, error) {
+	if w == nil {
+		return nil, fmt.Errorf("excelio: writer must not be nil")
+	}
+	return newWorkbook(w, ""), nil
+}
+
+// AddSheet adds a new sheet to wb and returns a StreamWriter for rows of type T.
+// The sheet name comes from Sheet(...) (default "Sheet1", "Sheet2", ...);
+// Header / StartRow and converter options apply to this sheet only.
+// Sheets appear in the order they are added unless SetSheetOrder is used.
+//
+// Calling Close on the returned writer finishes the sheet but does not write
+// the file; Workbook.Close finishes any open sheets and writes everything.
+func AddSheet[T any](wb *Workbook, opts ...Option) (*StreamWriter[T], error) {
+	if wb == nil || wb.f == nil {
+		return nil, fmt.Errorf("excelio: workbook is nil")
+	}
+	if wb.closed {
+		return nil, fmt.Errorf("excelio: workbook is closed")
+	}
+
+	o := buildOptions(opts)
+
+	name := o.SheetName
+	if name == "" {
+		name = fmt.Sprintf("Sheet%d", len(wb.sheets)+1)
+	}
+	if wb.sheet(name) != nil {
+		return nil, fmt.Errorf("excelio: sheet %q already added", name)
+	}
+	o.sheetResolved = name
+	if o.Date1904 {
+		// The date system belongs to the workbook, so any sheet can select it.
+		if err := setDate1904(wb.f); err != nil {
+			return nil, err
+		}
+	}
+
+	var s *workbookSheet
+	sw, err := newStreamWriterWithSink[T](o, func() (rowSink, error) {
+		if name != wb.defaultSheet {
+			if _, err := wb.f.NewSheet(name); err != nil {
+				return nil, err
+			}
+			// Remove the initial default sheet as soon as real sheets exist,
+			// unless it was claimed by a previous AddSheet.
+			if wb.defaultSheet != "" && wb.sheet(wb.defaultSheet) == nil {
+				if err := wb.f.DeleteSheet(wb.defaultSheet); err != nil {
+					return nil, err
+				}
+				wb.defaultSheet = ""
+			}
+		}
+		esw, err := wb.f.NewStreamWriter(name)
+		if err != nil {
+			return nil, err
+		}
+		s = &workbookSheet{name: name, f: wb.f, sw: esw}
+		wb.sheets = append(wb.sheets, s)
+		return s, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.finish = sw.Close
+	return sw, nil
+}
+
+// sheet returns the added sheet with the given name, or nil.
+func (wb *Workbook) sheet(name string) *workbookSheet {
+	for _, s := range wb.sheets {
+		if s.name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// SetActiveSheet selects the sheet shown when the file is opened.
+// Default is the first sheet.
+func (wb *Workbook) SetActiveSheet(name string) error {
+	if wb.sheet(name) == nil {
+		return fmt.Errorf("excelio: sheet %q not found", name)
+	}
+	wb.active = name
+	return nil
+}
+
+// SetSheetOrder sets the tab order of the listed sheets, applied on Close.
+// Sheets not listed keep their relative order after the listed ones.
+func (wb *Workbook) SetSheetOrder(names ...string) error {
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if wb.sheet(name) == nil {
+			return fmt.Errorf("excelio: sheet %q not found", name)
+		}
+		if seen[name] {
+			return fmt.Errorf("excelio: sheet %q listed twice", name)
+		}
+		seen[name] = true
+	}
+	wb.order = names
+	return nil
+}
+
+// SheetNames returns the names of the added sheets in insertion order.
+func (wb *Workbook) SheetNames() []string {
+	names := make([]string, len(wb.sheets))
+	for i, s := range wb.sheets {
+		names[i] = s.name
+	}
+	return names
+}
+
+// Close finishes all sheets, applies sheet order and active sheet, and writes
+// the workbook to its io.Writer or file path.
+// It is safe to call Close multiple times; subsequent calls are no-ops.
+func (wb *Workbook) Close() error {
+	if wb == nil || wb.f == nil || wb.closed {
+		return nil
+	}
+	wb.closed = true
+	defer wb.f.Close()
+
+	for _, s := range wb.sheets {
+		finish := s.close
+		if s.finish != nil {
+			finish = s.finish
+		}
+		if err := finish(); err != nil {
+			return err
+		}
+	}
+
+	// Move each listed sheet in front of the sheet currently at its position.
+	for i, name := range wb.order {
+		if cur := wb.f.GetSheetList(); i < len(cur) && cur[i] != name {
+			if err := wb.f.MoveSheet(name, cur[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	active := wb.active
+	if active == "" {
+		if list := wb.f.GetSheetList(); len(list) > 0 {
+			active = list[0]
+		}
+	}
+	if active != "" {
+		idx, err := wb.f.GetSheetIndex(active)
+		if err != nil {
+			return err
+		}
+		wb.f.SetActiveSheet(idx)
+	}
+
+	if wb.out != nil {
+		return wb.f.Write(wb.out)
+	}
+	return wb.f.SaveAs(wb.path)
+}
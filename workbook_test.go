@@ -0,0 +1,186 @@
+package excelio
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type wbSized struct {
+	Code  string `excel:"Code"`
+	Sizes []int  `excel:"Size {n}"`
+}
+
+// A sheet whose header waits for the first row still gets it when the
+// workbook is closed without rows or an explicit StreamWriter.Close.
+func TestWorkbookPendingHeader(t *testing.T) {
+	var buf bytes.Buffer
+	wb, err := NewWorkbook(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AddSheet[wbSized](wb, Sheet("Empty")); err != nil {
+		t.Fatal(err)
+	}
+	if err := wb.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := excelize.OpenReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := f.GetRows("Empty")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rows, [][]string{{"Code"}}) {
+		t.Errorf("rows = %q, want the header row", rows)
+	}
+}
+
+type wbOrder struct {
+	ID    string  `excel:"ID"`
+	Total float64 `excel:"Total"`
+}
+
+type wbLine struct {
+	Order string `excel:"Order"`
+	Qty   int    `excel:"Qty"`
+}
+
+// buildWorkbook writes orders and lines sheets to wb, ordered Lines first,
+// with Orders active.
+func buildWorkbook(t *testing.T, wb *Workbook, orders []wbOrder, lines []wbLine) {
+	t.Helper()
+	ow, err := AddSheet[wbOrder](wb, Sheet("Orders"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lw, err := AddSheet[wbLine](wb, Sheet("Lines"), Header(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AddSheet[wbLine](wb, Sheet("Orders")); err == nil || !strings.Contains(err.Error(), "already added") {
+		t.Errorf("duplicate sheet: err = %v", err)
+	}
+	if err := ow.WriteRows(orders); err != nil {
+		t.Fatal(err)
+	}
+	if err := lw.WriteRows(lines); err != nil {
+		t.Fatal(err)
+	}
+	if err := ow.Close(); err != nil { // closing a sheet early is allowed
+		t.Fatal(err)
+	}
+
+	if err := wb.SetSheetOrder("Missing"); err == nil {
+		t.Error("SetSheetOrder accepted an unknown sheet")
+	}
+	if err := wb.SetSheetOrder("Lines", "Lines"); err == nil {
+		t.Error("SetSheetOrder accepted a sheet listed twice")
+	}
+	if err := wb.SetActiveSheet("Missing"); err == nil {
+		t.Error("SetActiveSheet accepted an unknown sheet")
+	}
+	if err := wb.SetSheetOrder("Lines"); err != nil {
+		t.Fatal(err)
+	}
+	if err := wb.SetActiveSheet("Orders"); err != nil {
+		t.Fatal(err)
+	}
+	if got := wb.SheetNames(); !reflect.DeepEqual(got, []string{"Orders", "Lines"}) {
+		t.Errorf("SheetNames = %q", got)
+	}
+	if err := wb.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := wb.Close(); err != nil {
+		t.Errorf("second Close = %v", err)
+	}
+	if _, err := AddSheet[wbOrder](wb, Sheet("Late")); err == nil {
+		t.Error("AddSheet after Close succeeded")
+	}
+}
+
+func TestWorkbookRoundTrip(t *testing.T) {
+	orders := []wbOrder{{ID: "O1", Total: 12.5}, {ID: "O2", Total: 7}}
+	lines := []wbLine{{Order: "O1", Qty: 2}, {Order: "O1", Qty: 3}, {Order: "O2", Qty: 1}}
+
+	var buf bytes.Buffer
+	wb, err := NewWorkbook(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buildWorkbook(t, wb, orders, lines)
+
+	path := filepath.Join(t.TempDir(), "report.xlsx")
+	wb, err = NewWorkbookFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buildWorkbook(t, wb, orders, lines)
+	fromPath, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, data := range map[string][]byte{"writer": buf.Bytes(), "path": fromPath} {
+		f, err := excelize.OpenReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The default "Sheet1" is gone; Lines was moved first; Orders is active.
+		if got := f.GetSheetList(); !reflect.DeepEqual(got, []string{"Lines", "Orders"}) {
+			t.Errorf("%s: sheets = %q, want Lines, Orders", name, got)
+		}
+		if got := f.GetSheetName(f.GetActiveSheetIndex()); got != "Orders" {
+			t.Errorf("%s: active sheet = %q, want Orders", name, got)
+		}
+		_ = f.Close()
+
+		var gotOrders []wbOrder
+		var gotLines []wbLine
+		_, err = ReadSheets(bytes.NewReader(data),
+			SheetInto(&gotOrders, Sheet("Orders")),
+			SheetInto(&gotLines, Sheet("Lines"), Header(2)),
+		)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !reflect.DeepEqual(gotOrders, orders) || !reflect.DeepEqual(gotLines, lines) {
+			t.Errorf("%s: read back %+v, %+v", name, gotOrders, gotLines)
+		}
+	}
+}
+
+// An unnamed first sheet takes over the default "Sheet1".
+func TestWorkbookDefaultSheet(t *testing.T) {
+	var buf bytes.Buffer
+	wb, _ := NewWorkbook(&buf)
+	sw, err := AddSheet[wbOrder](wb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = sw.WriteRows([]wbOrder{{ID: "O1", Total: 1}})
+	if _, err := AddSheet[wbLine](wb); err != nil {
+		t.Fatal(err)
+	}
+	if err := wb.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if got := f.GetSheetList(); !reflect.DeepEqual(got, []string{"Sheet1", "Sheet2"}) {
+		t.Errorf("sheets = %q, want Sheet1, Sheet2", got)
+	}
+}